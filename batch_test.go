@@ -0,0 +1,156 @@
+package lda
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPredictParallel(t *testing.T) {
+	x, y, _ := syntheticData(20000, 6, 4)
+	var ld LD
+	if _, err := ld.PredictParallel([][]float64{x.RawRowView(0)}, 2); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	r, _ := x.Dims()
+	rows := make([][]float64, r)
+	want := make([]int, r)
+	for i := range rows {
+		rows[i] = x.RawRowView(i)
+		c, err := ld.Predict(rows[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		want[i] = c
+	}
+	for _, workers := range []int{0, 1, 3, 8} {
+		got, err := ld.PredictParallel(rows, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("unexpected prediction for row %d with %d workers got:%d, want:%d", i, workers, got[i], want[i])
+			}
+		}
+	}
+	if got, err := ld.PredictParallel(nil, 4); err != nil || len(got) != 0 {
+		t.Errorf("unexpected result for no rows got:%v, %v", got, err)
+	}
+	if _, err := ld.PredictParallel([][]float64{rows[0], rows[1][:3]}, 2); err == nil {
+		t.Error("expected an error for a short row")
+	}
+}
+
+func TestPredictBatchWithScores(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ld.PredictBatchWithScores(mat.NewDense(2, 3, nil)); err == nil {
+		t.Error("expected an error for a 3-column matrix")
+	}
+	classes, scores, err := ld.PredictBatchWithScores(dataMatrix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _ := dataMatrix.Dims()
+	if len(classes) != r || len(scores) != r {
+		t.Fatalf("unexpected lengths %d and %d for %d rows", len(classes), len(scores), r)
+	}
+	for i := 0; i < r; i++ {
+		row := dataMatrix.RawRowView(i)
+		want, _ := ld.Predict(row)
+		if classes[i] != want {
+			t.Errorf("unexpected class for row %d got:%d, want:%d", i, classes[i], want)
+		}
+		if math.IsNaN(scores[i]) || math.IsInf(scores[i], 0) {
+			t.Errorf("score of row %d is not finite: %v", i, scores[i])
+		}
+		dec, _ := ld.DecisionFunction(row)
+		if scores[i] != dec[classes[i]] {
+			t.Errorf("unexpected score for row %d got:%v, want:%v", i, scores[i], dec[classes[i]])
+		}
+	}
+
+	// A class mean scores better than a point far from every class
+	means := ld.ClassMeans()
+	far := mat.NewDense(1, 4, []float64{30, 30, 30, 30})
+	_, meanScores, err := ld.PredictBatchWithScores(means)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, farScores, err := ld.PredictBatchWithScores(far)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range meanScores {
+		if s <= farScores[0] {
+			t.Errorf("mean of class %d scores %v, no better than a distant point %v", i, s, farScores[0])
+		}
+	}
+}
+
+func TestPredictBatch(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if _, err := ld.PredictBatch(dataMatrix); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ld.PredictBatch(mat.NewDense(2, 3, nil)); err == nil {
+		t.Error("expected an error for a 3-column matrix")
+	}
+	check := func(ld *LD, x *mat.Dense) {
+		t.Helper()
+		classes, err := ld.PredictBatch(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, _ := x.Dims()
+		if len(classes) != r {
+			t.Fatalf("unexpected number of predictions got:%d, want:%d", len(classes), r)
+		}
+		for i := 0; i < r; i++ {
+			want, _ := ld.Predict(x.RawRowView(i))
+			if classes[i] != want {
+				t.Errorf("unexpected class for row %d got:%d, want:%d", i, classes[i], want)
+			}
+		}
+	}
+	check(&ld, dataMatrix)
+
+	// Standardized models and remapped labels
+	x, y, _ := syntheticData(1000, 8, 4)
+	for i := range y {
+		y[i] = 10 * y[i]
+	}
+	var remapped LD
+	remapped.SetStandardize(true)
+	if err := remapped.FitRemap(x, y); err != nil {
+		t.Fatal(err)
+	}
+	check(&remapped, x)
+}
+
+func BenchmarkPredictBatch(b *testing.B) {
+	x, y, _ := syntheticData(10000, 20, 4)
+	var ld LD
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ld.PredictBatch(x); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package lda
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// BootstrapEigenvalues estimates how stable the discriminant eigenvalues are
+// under resampling of the training data. Each of iters replicates draws the
+// rows of every class with replacement, keeping the class sizes, and fits a
+// model with the same settings as ld. The fitted model of ld is not changed.
+//
+// Parameter x is a matrix of input/training data.
+// Parameter y is an array of input/training labels in [0,k).
+// Parameter iters is the number of bootstrap replicates, at least 2.
+// Parameter rnd is the source of randomness, so results can be reproduced.
+// Returns the mean and standard deviation over the replicates of each of the
+// k-1 largest eigenvalues, or of all p if there are fewer features, largest
+// first.
+func (ld *LD) BootstrapEigenvalues(x mat.Matrix, y []int, iters int, rnd *rand.Rand) (mean, stddev []float64, err error) {
+	if iters < 2 {
+		return nil, nil, fmt.Errorf("Invalid number of iterations %d", iters)
+	}
+	n, p := x.Dims()
+	k, err := classLabels(y, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	// There are at most min(k-1, p) discriminant directions
+	d := k - 1
+	if p < d {
+		d = p
+	}
+	rows := make([][]int, k)
+	for i, label := range y {
+		rows[label] = append(rows[label], i)
+	}
+
+	rep := ld.settings()
+	sample := mat.NewDense(n, p, nil)
+	labels := make([]int, n)
+	values := make([][]float64, iters)
+	for it := 0; it < iters; it++ {
+		i := 0
+		for c, idx := range rows {
+			for range idx {
+				row := idx[rnd.Intn(len(idx))]
+				for j := 0; j < p; j++ {
+					sample.Set(i, j, x.At(row, j))
+				}
+				labels[i] = c
+				i++
+			}
+		}
+		if err := rep.LinearDiscriminant(sample, labels); err != nil {
+			return nil, nil, fmt.Errorf("Bootstrap replicate %d: %v", it, err)
+		}
+		values[it] = rep.EigenValues()[:d]
+	}
+
+	mean = make([]float64, d)
+	stddev = make([]float64, d)
+	for j := range mean {
+		for _, v := range values {
+			mean[j] += v[j]
+		}
+		mean[j] /= float64(iters)
+		for _, v := range values {
+			stddev[j] += (v[j] - mean[j]) * (v[j] - mean[j])
+		}
+		stddev[j] = math.Sqrt(stddev[j] / float64(iters-1))
+	}
+	return mean, stddev, nil
+}
@@ -0,0 +1,90 @@
+package lda
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestBootstrapEigenvalues(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if _, _, err := ld.BootstrapEigenvalues(dataMatrix, labels, 1, rand.New(rand.NewSource(1))); err == nil {
+		t.Error("expected an error for one iteration")
+	}
+	mean, stddev, err := ld.BootstrapEigenvalues(dataMatrix, labels, 50, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mean) != 2 || len(stddev) != 2 {
+		t.Fatalf("unexpected lengths %d and %d for 3 classes", len(mean), len(stddev))
+	}
+	// The first eigenvalue of the full data set is about 4744
+	if mean[0] < 3000 || mean[0] > 8000 {
+		t.Errorf("implausible bootstrap mean of the first eigenvalue %v", mean[0])
+	}
+	for j, s := range stddev {
+		if math.IsNaN(s) || math.IsInf(s, 0) || s <= 0 {
+			t.Errorf("unexpected standard deviation of eigenvalue %d got:%v", j, s)
+		}
+	}
+	if mean[0] < mean[1] {
+		t.Errorf("eigenvalues are not in decreasing order got:%v", mean)
+	}
+	if ld.mu != nil {
+		t.Error("BootstrapEigenvalues changed the model")
+	}
+
+	again, _, err := ld.BootstrapEigenvalues(dataMatrix, labels, 50, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again[0] != mean[0] || again[1] != mean[1] {
+		t.Errorf("results with the same seed differ got:%v and %v", mean, again)
+	}
+}
+
+func TestBootstrapEigenvaluesOneFeature(t *testing.T) {
+	// With one feature and three classes there is a single discriminant
+	// direction rather than k-1 = 2
+	x := mat.NewDense(12, 1, []float64{
+		0, 1, 2, 1,
+		5, 6, 7, 6,
+		10, 11, 12, 11,
+	})
+	y := []int{0, 0, 0, 0, 1, 1, 1, 1, 2, 2, 2, 2}
+	var ld LD
+	mean, stddev, err := ld.BootstrapEigenvalues(x, y, 20, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mean) != 1 || len(stddev) != 1 {
+		t.Fatalf("unexpected lengths %d and %d for one feature", len(mean), len(stddev))
+	}
+	if !(mean[0] > 0) || math.IsInf(mean[0], 0) {
+		t.Errorf("unexpected bootstrap mean of the eigenvalue %v", mean[0])
+	}
+}
+
+func TestBootstrapEigenvaluesSettings(t *testing.T) {
+	// The replicates are fit with the ridge of ld, which lowers the
+	// eigenvalues
+	dataMatrix, labels, _ := loadIris(t)
+	var plain, ridge LD
+	if err := ridge.SetRidge(1); err != nil {
+		t.Fatal(err)
+	}
+	want, _, err := plain.BootstrapEigenvalues(dataMatrix, labels, 10, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := ridge.BootstrapEigenvalues(dataMatrix, labels, 10, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !(got[0] < want[0]) {
+		t.Errorf("ridge not applied to the replicates got:%v, without ridge:%v", got[0], want[0])
+	}
+}
@@ -0,0 +1,101 @@
+package lda
+
+import (
+	"container/list"
+	"encoding/binary"
+	"math"
+	"sync"
+)
+
+// CachedPredictor wraps a fitted LD and remembers the class predicted for
+// recently seen inputs, for applications that classify the same feature
+// vectors repeatedly. It is safe to use from multiple goroutines. The model
+// must not be refit or updated while the predictor is in use, since cached
+// classes would no longer match it.
+type CachedPredictor struct {
+	ld         *LD
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // most recently used at the front
+	hits    int
+}
+
+// cacheEntry is the value stored in each element of CachedPredictor.lru.
+type cacheEntry struct {
+	key   string
+	class int
+}
+
+// NewCachedPredictor returns a CachedPredictor for the model that holds at
+// most maxEntries predictions, evicting the least recently used one when it
+// is full. If maxEntries is zero or negative, nothing is cached.
+func (ld *LD) NewCachedPredictor(maxEntries int) *CachedPredictor {
+	return &CachedPredictor{
+		ld:         ld,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// Predict returns the same class as LD.Predict, looking x up in the cache
+// first. Inputs are matched on their exact bit patterns. Errors aren't
+// cached.
+func (cp *CachedPredictor) Predict(x []float64) (int, error) {
+	key := cacheKey(x)
+	cp.mu.Lock()
+	if e, ok := cp.entries[key]; ok {
+		cp.lru.MoveToFront(e)
+		cp.hits++
+		class := e.Value.(*cacheEntry).class
+		cp.mu.Unlock()
+		return class, nil
+	}
+	cp.mu.Unlock()
+
+	// Predict outside the lock so concurrent misses don't serialize
+	class, err := cp.ld.Predict(x)
+	if err != nil || cp.maxEntries <= 0 {
+		return class, err
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	if e, ok := cp.entries[key]; ok {
+		// Another goroutine stored it in the meantime
+		cp.lru.MoveToFront(e)
+		return class, nil
+	}
+	cp.entries[key] = cp.lru.PushFront(&cacheEntry{key: key, class: class})
+	for cp.lru.Len() > cp.maxEntries {
+		oldest := cp.lru.Back()
+		cp.lru.Remove(oldest)
+		delete(cp.entries, oldest.Value.(*cacheEntry).key)
+	}
+	return class, nil
+}
+
+// Hits returns the number of calls to Predict answered from the cache.
+func (cp *CachedPredictor) Hits() int {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.hits
+}
+
+// Len returns the number of predictions currently cached.
+func (cp *CachedPredictor) Len() int {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.lru.Len()
+}
+
+// cacheKey encodes the bits of x as a string usable as a map key.
+func cacheKey(x []float64) string {
+	b := make([]byte, 8*len(x))
+	for i, v := range x {
+		binary.LittleEndian.PutUint64(b[8*i:], math.Float64bits(v))
+	}
+	return string(b)
+}
@@ -0,0 +1,84 @@
+package lda
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCachedPredictor(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	cp := ld.NewCachedPredictor(2)
+	x := []float64{7.7, 3.0, 6.1, 2.3}
+	want, err := ld.Predict(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		got, err := cp.Predict(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("unexpected cached prediction got:%d, want:%d", got, want)
+		}
+	}
+	if cp.Hits() != 1 {
+		t.Errorf("unexpected number of cache hits got:%d, want:1", cp.Hits())
+	}
+
+	// Filling the cache evicts the least recently used entry, x
+	for _, row := range [][]float64{{5.1, 3.5, 1.4, 0.2}, {5.7, 2.8, 4.1, 1.3}} {
+		if _, err := cp.Predict(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cp.Len() != 2 {
+		t.Errorf("unexpected cache size got:%d, want:2", cp.Len())
+	}
+	if _, err := cp.Predict(x); err != nil {
+		t.Fatal(err)
+	}
+	if cp.Hits() != 1 {
+		t.Errorf("expected a miss after eviction, got %d hits", cp.Hits())
+	}
+
+	if _, err := cp.Predict([]float64{1, 2}); err == nil {
+		t.Error("expected an error for the wrong number of features")
+	}
+	if cp.Len() != 2 {
+		t.Errorf("errors should not be cached, cache size got:%d", cp.Len())
+	}
+}
+
+func TestCachedPredictorConcurrent(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	cp := ld.NewCachedPredictor(16)
+	r, _ := dataMatrix.Dims()
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < r; i++ {
+				got, err := cp.Predict(dataMatrix.RawRowView(i))
+				want, _ := ld.Predict(dataMatrix.RawRowView(i))
+				if err != nil || got != want {
+					t.Errorf("unexpected prediction for row %d got:%d, want:%d (%v)", i, got, want, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if cp.Len() > 16 {
+		t.Errorf("cache grew past its limit to %d entries", cp.Len())
+	}
+}
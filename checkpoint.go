@@ -0,0 +1,272 @@
+package lda
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// checkpoint holds the sufficient statistics of a fitted model along with
+// its settings.
+type checkpoint struct {
+	N, P, K int
+	Counts  []int     // Number of instances in each class
+	Means   []float64 // Row-major k×p class means
+	Scatter []float64 // Row-major p×p within-class scatter matrix
+
+	Tol       float64
+	Priors    []float64
+	Weights   []float64
+	Solver    Solver
+	Shrinkage float64
+	Ridge     float64
+	Diagonal  bool
+	Classes   []string
+	Labels    []int
+
+	Standardize   bool
+	Center, Scale []float64
+
+	TransformOnly bool
+
+	ScoreMean, ScoreStd []float64 // Statistics of the training scores, see StandardizedScores
+
+	Partial *partialCheckpoint // Statistics accumulated by PartialFit, if not yet finalized
+}
+
+// partialCheckpoint holds the statistics accumulated by PartialFit.
+type partialCheckpoint struct {
+	P       int
+	Counts  []int     // Number of instances in each class seen so far
+	Means   []float64 // Row-major class means, p values per class
+	Scatter []float64 // Row-major p×p within-class scatter matrix
+}
+
+// Checkpoint serializes the sufficient statistics of a fitted model: the
+// class counts, the class means and the within-class scatter matrix. A model
+// restored with RestoreCheckpoint can continue to be updated with AddSample
+// and RemoveSample, so a long-running fit can be paused and resumed across
+// process restarts.
+//
+// The statistics accumulated by PartialFit can be checkpointed before
+// Finalize is called. The restored model then continues with PartialFit
+// and Finalize.
+func (ld *LD) Checkpoint() ([]byte, error) {
+	cp, err := ld.checkpoint()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(cp); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// checkpoint returns the sufficient statistics and settings of a fitted
+// model, or the statistics accumulated by PartialFit.
+func (ld *LD) checkpoint() (*checkpoint, error) {
+	if acc := ld.partial; acc != nil {
+		means := make([]float64, 0, len(acc.mu)*acc.p)
+		for _, row := range acc.mu {
+			means = append(means, row...)
+		}
+		return &checkpoint{
+			Tol:       ld.tol,
+			Priors:    ld.priors,
+			Weights:   ld.weights,
+			Solver:    ld.solver,
+			Shrinkage: ld.shrink,
+			Ridge:     ld.ridge,
+			Diagonal:  ld.diag,
+
+			Partial: &partialCheckpoint{
+				P:       acc.p,
+				Counts:  acc.ni,
+				Means:   means,
+				Scatter: mat.DenseCopyOf(acc.cw).RawMatrix().Data,
+			},
+		}, nil
+	}
+	if !ld.ok {
+		return nil, fmt.Errorf("Model has not been fit")
+	}
+	return &checkpoint{
+		N:         ld.n,
+		P:         ld.p,
+		K:         ld.k,
+		Counts:    ld.ni,
+		Means:     mat.DenseCopyOf(ld.mu).RawMatrix().Data,
+		Scatter:   mat.DenseCopyOf(ld.cw).RawMatrix().Data,
+		Tol:       ld.tol,
+		Priors:    ld.priors,
+		Weights:   ld.weights,
+		Solver:    ld.solver,
+		Shrinkage: ld.shrink,
+		Ridge:     ld.ridge,
+		Diagonal:  ld.diag,
+		Classes:   ld.classes,
+		Labels:    ld.labels,
+
+		Standardize: ld.standardize,
+		Center:      ld.center,
+		Scale:       ld.scale,
+
+		TransformOnly: ld.transformOnly,
+
+		ScoreMean: ld.scoreMean,
+		ScoreStd:  ld.scoreStd,
+	}, nil
+}
+
+// RestoreCheckpoint rebuilds a model from data produced by Checkpoint and
+// solves its eigenvalue problem again. Statistics accumulated by PartialFit
+// are restored as they were, to be finalized later.
+func RestoreCheckpoint(data []byte) (*LD, error) {
+	var cp checkpoint
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&cp); err != nil {
+		return nil, err
+	}
+	return cp.restore()
+}
+
+// restore rebuilds the model described by the checkpoint and solves its
+// eigenvalue problem.
+func (cp *checkpoint) restore() (*LD, error) {
+	if cp.Partial != nil {
+		return cp.restorePartial()
+	}
+	if cp.P < 1 || cp.K < 2 || len(cp.Counts) != cp.K ||
+		len(cp.Means) != cp.K*cp.P || len(cp.Scatter) != cp.P*cp.P {
+		return nil, fmt.Errorf("Invalid checkpoint")
+	}
+	if cp.Labels != nil && len(cp.Labels) != cp.K {
+		return nil, fmt.Errorf("Checkpoint has %d labels for %d classes", len(cp.Labels), cp.K)
+	}
+	if cp.Classes != nil && len(cp.Classes) != cp.K {
+		return nil, fmt.Errorf("Checkpoint has %d class names for %d classes", len(cp.Classes), cp.K)
+	}
+	if len(cp.Center) != len(cp.Scale) || (cp.Scale != nil && len(cp.Scale) != cp.P) {
+		return nil, fmt.Errorf("Checkpoint has %d centers and %d scales for %d features", len(cp.Center), len(cp.Scale), cp.P)
+	}
+
+	ld := &LD{
+		n:       cp.N,
+		p:       cp.P,
+		k:       cp.K,
+		ni:      cp.Counts,
+		mu:      mat.NewDense(cp.K, cp.P, cp.Means),
+		cw:      mat.NewSymDense(cp.P, cp.Scatter),
+		tol:     cp.Tol,
+		priors:  cp.Priors,
+		weights: cp.Weights,
+		solver:  cp.Solver,
+		shrink:  cp.Shrinkage,
+		ridge:   cp.Ridge,
+		diag:    cp.Diagonal,
+		classes: cp.Classes,
+		labels:  cp.Labels,
+
+		standardize: cp.Standardize,
+		center:      cp.Center,
+		scale:       cp.Scale,
+
+		transformOnly: cp.TransformOnly,
+	}
+
+	if err := ld.refit(); err != nil {
+		return nil, err
+	}
+	if len(cp.ScoreMean) == cp.K && len(cp.ScoreStd) == cp.K {
+		ld.scoreMean, ld.scoreStd = cp.ScoreMean, cp.ScoreStd
+	}
+	return ld, nil
+}
+
+// restorePartial rebuilds a model holding the statistics accumulated by
+// PartialFit.
+func (cp *checkpoint) restorePartial() (*LD, error) {
+	pc := cp.Partial
+	k := len(pc.Counts)
+	if pc.P < 1 || len(pc.Means) != k*pc.P || len(pc.Scatter) != pc.P*pc.P {
+		return nil, fmt.Errorf("Invalid checkpoint")
+	}
+	for _, count := range pc.Counts {
+		if count < 0 {
+			return nil, fmt.Errorf("Invalid checkpoint")
+		}
+	}
+
+	acc := &partialStats{
+		p:  pc.P,
+		ni: pc.Counts,
+		mu: make([][]float64, k),
+		cw: mat.NewSymDense(pc.P, pc.Scatter),
+	}
+	for c := range acc.mu {
+		acc.mu[c] = pc.Means[c*pc.P : (c+1)*pc.P]
+	}
+	return &LD{
+		tol:     cp.Tol,
+		priors:  cp.Priors,
+		weights: cp.Weights,
+		solver:  cp.Solver,
+		shrink:  cp.Shrinkage,
+		ridge:   cp.Ridge,
+		diag:    cp.Diagonal,
+		partial: acc,
+	}, nil
+}
+
+// MarshalJSON encodes a fitted model as JSON, in the same form as
+// Checkpoint: the sufficient statistics and settings, from which
+// UnmarshalJSON solves the model again. This lets a model trained offline
+// be embedded in a service without the training data.
+func (ld *LD) MarshalJSON() ([]byte, error) {
+	cp, err := ld.checkpoint()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(cp)
+}
+
+// UnmarshalJSON replaces ld with the model encoded by MarshalJSON. The
+// logger set by SetDebug is kept.
+func (ld *LD) UnmarshalJSON(data []byte) error {
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return err
+	}
+	return ld.restoreFrom(&cp)
+}
+
+// GobEncode encodes a fitted model like Checkpoint, so an LD can be
+// stored with encoding/gob, on its own or as part of a larger value.
+func (ld *LD) GobEncode() ([]byte, error) {
+	return ld.Checkpoint()
+}
+
+// GobDecode replaces ld with the model encoded by GobEncode. The logger
+// set by SetDebug is kept.
+func (ld *LD) GobDecode(data []byte) error {
+	var cp checkpoint
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&cp); err != nil {
+		return err
+	}
+	return ld.restoreFrom(&cp)
+}
+
+// restoreFrom replaces ld with the model restored from cp, leaving ld
+// unchanged if it can't be restored.
+func (ld *LD) restoreFrom(cp *checkpoint) error {
+	restored, err := cp.restore()
+	if err != nil {
+		return err
+	}
+	restored.logger = ld.logger
+	*ld = *restored
+	return nil
+}
@@ -0,0 +1,188 @@
+package lda
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCheckpoint(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	r, c := dataMatrix.Dims()
+
+	// Split the rows alternately into two chunks so each class is
+	// represented in both
+	var chunks [2]*mat.Dense
+	var chunkLabels [2][]int
+	for h := range chunks {
+		chunks[h] = mat.NewDense(r/2, c, nil)
+		for i := h; i < r; i += 2 {
+			chunks[h].SetRow(i/2, dataMatrix.RawRowView(i))
+			chunkLabels[h] = append(chunkLabels[h], labels[i])
+		}
+	}
+	var ld LD
+	if _, err := ld.Checkpoint(); err == nil {
+		t.Error("expected an error checkpointing an unfitted model")
+	}
+	if err := ld.PartialFit(chunks[0], chunkLabels[0]); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ld.Checkpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := RestoreCheckpoint(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.PartialFit(chunks[1], chunkLabels[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.Finalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	var batch LD
+	if err := batch.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	const epsilon = 1e-9
+	if restored.n != batch.n {
+		t.Errorf("unexpected sample count got:%d, want:%d", restored.n, batch.n)
+	}
+	if !mat.EqualApprox(restored.mu, batch.mu, epsilon) {
+		t.Error("unexpected class means after resuming")
+	}
+	if !mat.EqualApprox(restored.cw, batch.cw, epsilon) {
+		t.Error("unexpected within-class scatter after resuming")
+	}
+	for i := 0; i < 2; i++ {
+		if math.Abs(cmplx.Abs(restored.evals[i])-cmplx.Abs(batch.evals[i])) > 1e-6 {
+			t.Errorf("unexpected eigenvalue %d got:%v, want:%v", i, restored.evals[i], batch.evals[i])
+		}
+	}
+
+	if _, err := RestoreCheckpoint([]byte("garbage")); err == nil {
+		t.Error("expected an error restoring invalid data")
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if _, err := json.Marshal(&ld); err == nil {
+		t.Error("expected an error encoding an unfitted model")
+	}
+	ld.SetStandardize(true)
+	if err := ld.SetPriors([]float64{0.2, 0.3, 0.5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(&ld)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded LD
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	checkSameModel(t, &decoded, &ld, dataMatrix)
+
+	if err := json.Unmarshal([]byte(`{"P": 4, "K": 3}`), &decoded); err == nil {
+		t.Error("expected an error decoding an invalid model")
+	}
+	// Labels, class names and standardization that don't match the
+	// dimensions of the model are rejected
+	for field, value := range map[string]interface{}{
+		"Labels":  []int{10, 20},
+		"Classes": []string{"a", "b", "c", "d"},
+		"Center":  []float64{0, 0, 0},
+		"Scale":   []float64{1, 1, 1, 1, 1},
+	} {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			t.Fatal(err)
+		}
+		fields[field] = value
+		invalid, err := json.Marshal(fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(invalid, &decoded); err == nil {
+			t.Errorf("expected an error decoding a model with invalid %s", field)
+		}
+	}
+	if !decoded.IsFitted() {
+		t.Error("expected a failed decode to leave the model unchanged")
+	}
+}
+
+func TestGobEncode(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+
+	// An LD can be encoded as part of a larger value
+	type service struct {
+		Name  string
+		Model *LD
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(service{Name: "iris", Model: &ld}); err != nil {
+		t.Fatal(err)
+	}
+	var decoded service
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Name != "iris" {
+		t.Errorf("unexpected name got:%q, want:iris", decoded.Name)
+	}
+	checkSameModel(t, decoded.Model, &ld, dataMatrix)
+}
+
+// checkSameModel checks that got transforms, predicts and scores x like want.
+func checkSameModel(t *testing.T, got, want *LD, x *mat.Dense) {
+	t.Helper()
+	const epsilon = 1e-9
+	gotT, err := got.Transform(x, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantT, _ := want.Transform(x, 2)
+	if !mat.EqualApprox(gotT, wantT, epsilon) {
+		t.Error("unexpected transform of the decoded model")
+	}
+	r, _ := x.Dims()
+	for i := 0; i < r; i++ {
+		row := x.RawRowView(i)
+		g, err := got.Predict(row)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w, _ := want.Predict(row); g != w {
+			t.Errorf("unexpected prediction for row %d got:%d, want:%d", i, g, w)
+		}
+	}
+	gotS, err := got.StandardizedScores(x.RawRowView(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantS, _ := want.StandardizedScores(x.RawRowView(0))
+	for j := range wantS {
+		if math.Abs(gotS[j]-wantS[j]) > epsilon {
+			t.Errorf("unexpected standardized score %d got:%v, want:%v", j, gotS[j], wantS[j])
+		}
+	}
+}
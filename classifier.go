@@ -0,0 +1,15 @@
+package lda
+
+import "gonum.org/v1/gonum/mat"
+
+// Classifier is a model that can be trained on labeled data and then
+// predict the class of new observations. Labels are ints in [0,k).
+type Classifier interface {
+	Fit(x mat.Matrix, y []int) error
+	Predict(x []float64) (int, error)
+}
+
+var (
+	_ Classifier = (*LD)(nil)
+	_ Classifier = (*QD)(nil)
+)
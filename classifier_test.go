@@ -0,0 +1,28 @@
+package lda
+
+import "testing"
+
+func TestClassifier(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	for name, c := range map[string]Classifier{
+		"LD": &LD{},
+		"QD": &QD{},
+	} {
+		if err := c.Fit(dataMatrix, labels); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var correct int
+		for i, want := range labels {
+			got, err := c.Predict(dataMatrix.RawRowView(i))
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if got == want {
+				correct++
+			}
+		}
+		if accuracy := float64(correct) / float64(len(labels)); accuracy < 0.95 {
+			t.Errorf("%s: unexpected accuracy got:%v, want at least 0.95", name, accuracy)
+		}
+	}
+}
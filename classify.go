@@ -0,0 +1,71 @@
+package lda
+
+import "math"
+
+// Result is the outcome of classifying one observation with Classify.
+type Result struct {
+	Class  int       // Predicted class, as returned by Predict
+	Score  []float64 // Discriminant score of each class, as returned by DecisionFunction
+	Prob   []float64 // Posterior probability of each class, as returned by PredictProba
+	Margin float64   // Difference between the best and second best scores
+}
+
+// Classify computes the prediction, the discriminant scores, the posterior
+// probabilities and the margin for x in one pass, for callers that need more
+// than one of them. Score and Prob are indexed by class, while Class is
+// mapped back to the original label if the model was fit with FitRemap.
+// Ties are broken like Predict, and give a margin of 0.
+//
+// Parameter x is the set of data to classify.
+func (ld *LD) Classify(x []float64) (Result, error) {
+	scores, err := ld.DecisionFunction(x)
+	if err != nil {
+		return Result{}, err
+	}
+	class := argmax(scores)
+	best, second := scores[class], math.Inf(-1)
+	for i, f := range scores {
+		if i != class && f > second {
+			second = f
+		}
+	}
+	if ld.labels != nil {
+		class = ld.labels[class]
+	}
+	prob := make([]float64, len(scores))
+	copy(prob, scores)
+	softmax(prob)
+	return Result{Class: class, Score: scores, Prob: prob, Margin: best - second}, nil
+}
+
+// argmax returns the index of the largest score. If several scores are
+// equally large, the lowest index wins, so that predictions don't depend on
+// anything but the scores.
+func argmax(scores []float64) int {
+	best := 0
+	for i, f := range scores {
+		if f > scores[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+// softmax replaces the scores with their softmax in place, so they are
+// positive and sum to 1.
+func softmax(scores []float64) {
+	// Subtract the largest score before exponentiating so the
+	// softmax doesn't overflow.
+	max := math.Inf(-1)
+	for _, f := range scores {
+		max = math.Max(max, f)
+	}
+	var sum float64
+	for i, f := range scores {
+		scores[i] = math.Exp(f - max)
+		sum += scores[i]
+	}
+	for i := range scores {
+		scores[i] /= sum
+	}
+}
@@ -0,0 +1,97 @@
+package lda
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestClassify(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if _, err := ld.Classify([]float64{5.0, 3.3, 1.4, 0.2}); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	r, _ := dataMatrix.Dims()
+	for i := 0; i < r; i++ {
+		x := dataMatrix.RawRowView(i)
+		res, err := ld.Classify(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Score) != ld.k || len(res.Prob) != ld.k {
+			t.Fatalf("unexpected result lengths %d and %d for %d classes", len(res.Score), len(res.Prob), ld.k)
+		}
+		best, second := 0, -1
+		var sum float64
+		for c := range res.Score {
+			sum += res.Prob[c]
+			if res.Score[c] > res.Score[best] {
+				best, second = c, best
+			} else if c != best && (second < 0 || res.Score[c] > res.Score[second]) {
+				second = c
+			}
+			if res.Prob[c] > res.Prob[res.Class] {
+				t.Errorf("row %d: class %d is more probable than the predicted class %d", i, c, res.Class)
+			}
+		}
+		if res.Class != best {
+			t.Errorf("row %d: unexpected class got:%d, want argmax:%d", i, res.Class, best)
+		}
+		if math.Abs(sum-1) > 1e-12 {
+			t.Errorf("row %d: probabilities sum to %v", i, sum)
+		}
+		if want := res.Score[best] - res.Score[second]; math.Abs(res.Margin-want) > 1e-12 {
+			t.Errorf("row %d: unexpected margin got:%v, want:%v", i, res.Margin, want)
+		}
+		if c, _ := ld.Predict(x); c != res.Class {
+			t.Errorf("row %d: Predict got:%d, Classify got:%d", i, c, res.Class)
+		}
+	}
+}
+
+func TestPredictTieBreak(t *testing.T) {
+	// Class 1 is the mirror image of class 0 through the origin, so the
+	// origin is exactly as far from both class means
+	half := []float64{1, 2, 2, 1.5, 3, 3.5, 1.5, 1}
+	data := append([]float64(nil), half...)
+	for _, v := range half {
+		data = append(data, -v)
+	}
+	x := mat.NewDense(8, 2, data)
+	y := []int{0, 0, 0, 0, 1, 1, 1, 1}
+	var ld LD
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	origin := []float64{0, 0}
+	scores, err := ld.DecisionFunction(origin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scores[0] != scores[1] {
+		t.Fatalf("expected an exact tie got:%v", scores)
+	}
+
+	for i := 0; i < 10; i++ {
+		if c, _ := ld.Predict(origin); c != 0 {
+			t.Errorf("unexpected Predict tie-break got:%d, want:0", c)
+		}
+		if top, _ := ld.PredictTopK(origin, 2); top[0] != 0 || top[1] != 1 {
+			t.Errorf("unexpected PredictTopK tie-break got:%v, want:[0 1]", top)
+		}
+		if res, _ := ld.Classify(origin); res.Class != 0 || res.Margin != 0 {
+			t.Errorf("unexpected Classify tie-break got class %d and margin %v, want:0 and 0", res.Class, res.Margin)
+		}
+		if c, _ := ld.PredictNearestMean(origin, 1); c != 0 {
+			t.Errorf("unexpected PredictNearestMean tie-break got:%d, want:0", c)
+		}
+	}
+	if prob, _ := ld.PredictProba(origin); prob[0] != 0.5 || prob[1] != 0.5 {
+		t.Errorf("unexpected probabilities for a tie got:%v", prob)
+	}
+}
@@ -0,0 +1,150 @@
+package lda
+
+import (
+	"bytes"
+	"go/format"
+	"io"
+	"text/template"
+)
+
+// classifierTemplate is the source of the file emitted by GenerateGoClassifier.
+// Predict mirrors LD.Predict so the generated code classifies identically.
+var classifierTemplate = template.Must(template.New("classifier").Parse(`// Code generated by github.com/RadiusNetworks/lda. DO NOT EDIT.
+
+package {{.Package}}
+
+// Means holds the mean vector of each class.
+var Means = [][]float64{
+{{- range .Means}}
+	{ {{- range .}}{{printf "%v" .}}, {{end -}} },
+{{- end}}
+}
+
+// Vectors holds the discriminant eigenvectors, one per column.
+var Vectors = [][]float64{
+{{- range .Vectors}}
+	{ {{- range .}}{{printf "%v" .}}, {{end -}} },
+{{- end}}
+}
+
+// Variances holds the within-class variance along each eigenvector, or zero
+// for eigenvectors that carry no discriminant information.
+var Variances = []float64{ {{- range .Variances}}{{printf "%v" .}}, {{end -}} }
+
+// Constants holds the constant term of the discriminant function of each class.
+var Constants = []float64{ {{- range .Constants}}{{printf "%v" .}}, {{end -}} }
+{{- if .Labels}}
+
+// Labels holds the original label of each class, which Predict returns.
+var Labels = []int{ {{- range .Labels}}{{printf "%d" .}}, {{end -}} }
+{{- end}}
+{{- if .Classes}}
+
+// Classes holds the original string label of each class, which
+// PredictLabel returns.
+var Classes = []string{ {{- range .Classes}}{{printf "%q" .}}, {{end -}} }
+{{- end}}
+
+// Predict returns the class that x is most likely to be in.
+// x must have {{.Features}} elements.
+func Predict(x []float64) int {
+	y := 0
+	var max float64
+	d := make([]float64, len(x))
+	for i := range Means {
+		for j := range x {
+			d[j] = x[j] - Means[i][j]
+		}
+		var f float64
+		for j := range Variances {
+			if Variances[j] == 0 {
+				continue
+			}
+			var ux float64
+			for l := range d {
+				ux += Vectors[l][j] * d[l]
+			}
+			f += ux * ux / Variances[j]
+		}
+		f = Constants[i] - 0.5*f
+		if i == 0 || max < f {
+			max = f
+			y = i
+		}
+	}
+{{- if .Labels}}
+	return Labels[y]
+{{- else}}
+	return y
+{{- end}}
+}
+{{- if .Classes}}
+
+// PredictLabel returns the string label of the class that x is most likely
+// to be in.
+func PredictLabel(x []float64) string {
+	return Classes[Predict(x)]
+}
+{{- end}}
+`))
+
+// GenerateGoClassifier writes a self-contained Go source file implementing
+// the fitted model's Predict function. The generated file has no
+// dependencies outside the standard library. Like LD.Predict, the generated
+// Predict returns the original label of a model fit with FitRemap, and a
+// model fit with FitLabels also gets a PredictLabel function.
+//
+// Parameter w is the destination of the generated source.
+// Parameter packageName is the package clause of the generated file.
+func (ld *LD) GenerateGoClassifier(w io.Writer, packageName string) error {
+	if err := ld.checkClassifier(); err != nil {
+		return err
+	}
+	data := struct {
+		Package   string
+		Features  int
+		Means     [][]float64
+		Vectors   [][]float64
+		Variances []float64
+		Constants []float64
+		Labels    []int
+		Classes   []string
+	}{
+		Package:   packageName,
+		Features:  ld.p,
+		Variances: make([]float64, ld.p),
+		Constants: ld.ct,
+		Labels:    ld.labels,
+		Classes:   ld.classes,
+	}
+	// Standardization is folded into the generated means and vectors: the
+	// projection of (x-center)/scale - mu on an eigenvector v equals the
+	// projection of x - (center + scale*mu) on v/scale.
+	means := ld.ClassMeans()
+	for i := 0; i < ld.k; i++ {
+		data.Means = append(data.Means, means.RawRowView(i))
+	}
+	for i := 0; i < ld.p; i++ {
+		row := append([]float64(nil), ld.evecs.RawRowView(i)...)
+		if ld.scale != nil {
+			for j := range row {
+				row[j] /= ld.scale[i]
+			}
+		}
+		data.Vectors = append(data.Vectors, row)
+	}
+	for _, j := range ld.dirs {
+		data.Variances[j] = ld.wvar[j]
+	}
+
+	var buf bytes.Buffer
+	if err := classifierTemplate.Execute(&buf, data); err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
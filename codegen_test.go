@@ -0,0 +1,106 @@
+package lda
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const generatedMain = `package main
+
+import "fmt"
+
+func main() {
+	for _, x := range [][]float64{
+		{5.0, 3.3, 1.4, 0.2},
+		{5.1, 2.5, 3.0, 1.1},
+		{7.7, 3.0, 6.1, 2.3},
+	} {
+		fmt.Println(Predict(x))
+	}
+}
+`
+
+func TestGenerateGoClassifier(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go tool not available")
+	}
+	dataMatrix, labels, names := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	checkGeneratedClassifier(t, &ld, "Predict(x)", func(x []float64) string {
+		c, _ := ld.Predict(x)
+		return strconv.Itoa(c)
+	})
+
+	// Remapped labels are returned like LD.Predict returns them
+	remapped := make([]int, len(labels))
+	for i, label := range labels {
+		remapped[i] = 10*label + 5
+	}
+	var remap LD
+	if err := remap.FitRemap(dataMatrix, remapped); err != nil {
+		t.Fatal(err)
+	}
+	checkGeneratedClassifier(t, &remap, "Predict(x)", func(x []float64) string {
+		c, _ := remap.Predict(x)
+		return strconv.Itoa(c)
+	})
+
+	// String labels get PredictLabel
+	var named LD
+	if err := named.FitLabels(dataMatrix, names); err != nil {
+		t.Fatal(err)
+	}
+	checkGeneratedClassifier(t, &named, "PredictLabel(x)", func(x []float64) string {
+		label, _ := named.PredictLabel(x)
+		return label
+	})
+}
+
+// checkGeneratedClassifier compiles the classifier generated for ld with a
+// main function printing call for a few Iris samples, and compares the
+// output with want.
+func checkGeneratedClassifier(t *testing.T, ld *LD, call string, want func([]float64) string) {
+	t.Helper()
+	dir := t.TempDir()
+	f, err := os.Create(filepath.Join(dir, "classifier.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ld.GenerateGoClassifier(f, "main")
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, src := range map[string]string{
+		"go.mod":  "module generated\n\ngo 1.13\n",
+		"main.go": strings.Replace(generatedMain, "Predict(x)", call, 1),
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("running generated classifier failed: %v\n%s", err, out)
+	}
+	got := strings.Fields(string(out))
+	for i, x := range [][]float64{
+		{5.0, 3.3, 1.4, 0.2},
+		{5.1, 2.5, 3.0, 1.1},
+		{7.7, 3.0, 6.1, 2.3},
+	} {
+		if w := want(x); i >= len(got) || got[i] != w {
+			t.Errorf("unexpected generated prediction %d got:%v, want:%s", i, got, w)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package lda
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// LoadCSV reads comma-separated observations with one class column, such as
+// the Iris dataset. Every other column must be numeric.
+//
+// Parameter r is the source of the CSV data, which has no header row.
+// Parameter labelCol is the index of the class column.
+// Returns the n×p matrix of features in their original column order, the
+// class index of each row, and the original labels by class index. Labels
+// are encoded in order of first appearance, like FitLabels does.
+func LoadCSV(r io.Reader, labelCol int) (*mat.Dense, []int, []string, error) {
+	reader := csv.NewReader(r)
+	var data []float64
+	var y []int
+	var encoder LabelEncoder
+	var cols int
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if line == 1 {
+			cols = len(record)
+			if labelCol < 0 || labelCol >= cols {
+				return nil, nil, nil, fmt.Errorf("Label column %d is outside the %d columns", labelCol, cols)
+			}
+		}
+		for j, field := range record {
+			if j == labelCol {
+				y = append(y, encoder.add(field))
+				continue
+			}
+			v, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("Line %d, column %d: %v", line, j, err)
+			}
+			data = append(data, v)
+		}
+	}
+	if len(y) == 0 {
+		return nil, nil, nil, fmt.Errorf("No data to analyze")
+	}
+	if cols < 2 {
+		return nil, nil, nil, fmt.Errorf("No feature columns")
+	}
+	return mat.NewDense(len(y), cols-1, data), y, encoder.classes, nil
+}
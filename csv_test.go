@@ -0,0 +1,65 @@
+package lda
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadCSV(t *testing.T) {
+	f, err := os.Open("iris/iris.data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	x, y, classes, err := LoadCSV(f, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, c := x.Dims(); r != 150 || c != 4 {
+		t.Fatalf("unexpected dimensions got:%d×%d, want:150×4", r, c)
+	}
+	if len(y) != 150 {
+		t.Errorf("unexpected number of labels got:%d, want:150", len(y))
+	}
+	if len(classes) != 3 {
+		t.Errorf("unexpected number of classes got:%v, want:3", classes)
+	}
+
+	// LoadCSV agrees with the test loader
+	want, wantLabels, _ := loadIris(t)
+	for i := range y {
+		if y[i] != wantLabels[i] {
+			t.Fatalf("unexpected label %d got:%d, want:%d", i, y[i], wantLabels[i])
+		}
+		for j := 0; j < 4; j++ {
+			if x.At(i, j) != want.At(i, j) {
+				t.Fatalf("unexpected element (%d,%d) got:%v, want:%v", i, j, x.At(i, j), want.At(i, j))
+			}
+		}
+	}
+
+	for _, test := range []struct {
+		name     string
+		data     string
+		labelCol int
+	}{
+		{"ragged", "1,2,a\n3,b\n", 2},
+		{"non-numeric", "1,2,a\n3,x,b\n", 2},
+		{"label column", "1,2,a\n", 3},
+		{"empty", "", 0},
+	} {
+		if _, _, _, err := LoadCSV(strings.NewReader(test.data), test.labelCol); err == nil {
+			t.Errorf("expected an error for %s input", test.name)
+		}
+	}
+
+	// The label column may be anywhere
+	x, y, classes, err = LoadCSV(strings.NewReader("a,1,2\nb,3,4\na,5,6\n"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.At(2, 1) != 6 || y[2] != 0 || classes[1] != "b" {
+		t.Errorf("unexpected result got:%v %v %v", x, y, classes)
+	}
+}
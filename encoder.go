@@ -0,0 +1,66 @@
+package lda
+
+import "fmt"
+
+// LabelEncoder converts between string class labels and the contiguous int
+// labels in [0,k) that LinearDiscriminant requires. Labels are encoded in
+// order of first appearance, like FitLabels does.
+type LabelEncoder struct {
+	classes []string       // Original labels by code
+	codes   map[string]int // Code of each label
+}
+
+// Fit learns the distinct labels and returns their codes.
+func (e *LabelEncoder) Fit(labels []string) []int {
+	e.classes, e.codes = nil, nil
+	y := make([]int, len(labels))
+	for i, label := range labels {
+		y[i] = e.add(label)
+	}
+	return y
+}
+
+// add returns the code of label, assigning the next code if it is new.
+func (e *LabelEncoder) add(label string) int {
+	if e.codes == nil {
+		e.codes = map[string]int{}
+	}
+	c, ok := e.codes[label]
+	if !ok {
+		c = len(e.classes)
+		e.codes[label] = c
+		e.classes = append(e.classes, label)
+	}
+	return c
+}
+
+// Transform returns the codes of labels learned by Fit. It returns an error
+// for labels that were not seen by Fit.
+func (e *LabelEncoder) Transform(labels []string) ([]int, error) {
+	y := make([]int, len(labels))
+	for i, label := range labels {
+		c, ok := e.codes[label]
+		if !ok {
+			return nil, fmt.Errorf("Unknown label %q", label)
+		}
+		y[i] = c
+	}
+	return y, nil
+}
+
+// Inverse returns the original labels of codes.
+func (e *LabelEncoder) Inverse(codes []int) ([]string, error) {
+	labels := make([]string, len(codes))
+	for i, c := range codes {
+		if c < 0 || c >= len(e.classes) {
+			return nil, fmt.Errorf("Invalid class label %d", c)
+		}
+		labels[i] = e.classes[c]
+	}
+	return labels, nil
+}
+
+// Classes returns the original labels by code.
+func (e *LabelEncoder) Classes() []string {
+	return append([]string(nil), e.classes...)
+}
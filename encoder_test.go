@@ -0,0 +1,41 @@
+package lda
+
+import "testing"
+
+func TestLabelEncoder(t *testing.T) {
+	_, labels, species := loadIris(t)
+	var e LabelEncoder
+	codes := e.Fit(species)
+	for i := range codes {
+		if codes[i] != labels[i] {
+			t.Fatalf("unexpected code for row %d got:%d, want:%d", i, codes[i], labels[i])
+		}
+	}
+	if got := e.Classes(); len(got) != 3 || got[0] != "Iris-versicolor" {
+		t.Errorf("unexpected classes got:%v", got)
+	}
+
+	decoded, err := e.Inverse(codes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range species {
+		if decoded[i] != species[i] {
+			t.Fatalf("unexpected label for row %d got:%q, want:%q", i, decoded[i], species[i])
+		}
+	}
+
+	got, err := e.Transform([]string{"Iris-setosa", "Iris-virginica"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0] != 2 || got[1] != 1 {
+		t.Errorf("unexpected codes got:%v, want:[2 1]", got)
+	}
+	if _, err := e.Transform([]string{"Iris-unknown"}); err == nil {
+		t.Error("expected an error for an unseen label")
+	}
+	if _, err := e.Inverse([]int{3}); err == nil {
+		t.Error("expected an error for an invalid code")
+	}
+}
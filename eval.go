@@ -0,0 +1,319 @@
+package lda
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// TotalCost predicts the class of every row of x and sums the cost of each
+// prediction, where entry (i,j) of costMatrix is the cost of predicting class
+// j for a sample of true class i.
+//
+// Parameter x is the matrix of data to classify.
+// Parameter y holds the true class of each row of x.
+// Parameter costMatrix is a k×k matrix of costs.
+// Returns the sum of the costs over all rows.
+func (ld *LD) TotalCost(x mat.Matrix, y []int, costMatrix *mat.Dense) (float64, error) {
+	r, c := x.Dims()
+	if len(y) != r {
+		return 0, fmt.Errorf("The sizes of X and Y don't match")
+	}
+	if cr, cc := costMatrix.Dims(); cr != ld.k || cc != ld.k {
+		return 0, fmt.Errorf("Cost matrix is %d×%d, want %d×%d", cr, cc, ld.k, ld.k)
+	}
+	var total float64
+	row := make([]float64, c)
+	for i := 0; i < r; i++ {
+		if y[i] < 0 || y[i] >= ld.k {
+			return 0, fmt.Errorf("Invalid class label %d", y[i])
+		}
+		mat.Row(row, i, x)
+		pred, err := ld.predictClass(row)
+		if err != nil {
+			return 0, err
+		}
+		total += costMatrix.At(y[i], pred)
+	}
+	return total, nil
+}
+
+// CrossValidate estimates the accuracy of linear discriminant analysis on
+// unseen data with stratified k-fold cross-validation. The rows of each class
+// are dealt in turn to the folds, so every fold has about the same class
+// proportions as the whole data. A model is fit on all folds but one and
+// tested on the held-out fold, once for each fold.
+//
+// Parameter x is the matrix of input data.
+// Parameter y holds the class of each row of x, in [0,k).
+// Parameter folds is the number of folds. It must be at least 2 and no more
+// than the number of samples in the smallest class.
+// Returns the mean accuracy over the folds.
+func CrossValidate(x mat.Matrix, y []int, folds int) (accuracy float64, err error) {
+	r, c := x.Dims()
+	if len(y) != r {
+		return 0, fmt.Errorf("The sizes of X and Y don't match")
+	}
+	if folds < 2 {
+		return 0, fmt.Errorf("Need at least 2 folds, got %d", folds)
+	}
+	var counts []int
+	for _, label := range y {
+		if label < 0 {
+			return 0, fmt.Errorf("Invalid class label %d", label)
+		}
+		for label >= len(counts) {
+			counts = append(counts, 0)
+		}
+		counts[label]++
+	}
+	for class, count := range counts {
+		if count < folds {
+			return 0, fmt.Errorf("Class %d has %d samples, fewer than %d folds", class, count, folds)
+		}
+	}
+
+	// fold holds the fold each row is held out in
+	fold := make([]int, r)
+	seen := make([]int, len(counts))
+	for i, label := range y {
+		fold[i] = seen[label] % folds
+		seen[label]++
+	}
+
+	row := make([]float64, c)
+	for f := 0; f < folds; f++ {
+		var train, test []int
+		for i := 0; i < r; i++ {
+			if fold[i] == f {
+				test = append(test, i)
+			} else {
+				train = append(train, i)
+			}
+		}
+		trainX := mat.NewDense(len(train), c, nil)
+		trainY := make([]int, len(train))
+		for i, idx := range train {
+			mat.Row(row, idx, x)
+			trainX.SetRow(i, row)
+			trainY[i] = y[idx]
+		}
+		var ld LD
+		if err := ld.LinearDiscriminant(trainX, trainY); err != nil {
+			return 0, fmt.Errorf("Fold %d: %v", f, err)
+		}
+		var correct int
+		for _, idx := range test {
+			mat.Row(row, idx, x)
+			pred, err := ld.Predict(row)
+			if err != nil {
+				return 0, err
+			}
+			if pred == y[idx] {
+				correct++
+			}
+		}
+		accuracy += float64(correct) / float64(len(test))
+	}
+	return accuracy / float64(folds), nil
+}
+
+// TrainTestSplit splits the rows of x into a training and a test set,
+// holding out the same proportion of every class so both sets keep the class
+// proportions of the whole data. The rows of each class are shuffled with a
+// generator seeded by seed, so the same seed always gives the same split.
+// Both sets keep the rows in their original order.
+//
+// Parameter x is the matrix of input data.
+// Parameter y holds the class of each row of x, in [0,k).
+// Parameter testFrac is the proportion of each class in (0,1) to hold out,
+// rounded to the nearest row. Every class must end up with at least one row
+// in each set.
+func TrainTestSplit(x mat.Matrix, y []int, testFrac float64, seed int64) (xTrain, xTest *mat.Dense, yTrain, yTest []int, err error) {
+	r, c := x.Dims()
+	if len(y) != r {
+		return nil, nil, nil, nil, fmt.Errorf("The sizes of X and Y don't match")
+	}
+	if !(testFrac > 0 && testFrac < 1) {
+		return nil, nil, nil, nil, fmt.Errorf("Test fraction %v is outside (0,1)", testFrac)
+	}
+	var classes [][]int
+	for i, label := range y {
+		if label < 0 {
+			return nil, nil, nil, nil, fmt.Errorf("Invalid class label %d", label)
+		}
+		for label >= len(classes) {
+			classes = append(classes, nil)
+		}
+		classes[label] = append(classes[label], i)
+	}
+
+	rnd := rand.New(rand.NewSource(seed))
+	test := make([]bool, r)
+	for class, rows := range classes {
+		if len(rows) == 0 {
+			continue
+		}
+		nTest := int(math.Round(testFrac * float64(len(rows))))
+		if nTest < 1 || nTest >= len(rows) {
+			return nil, nil, nil, nil, fmt.Errorf("Class %d has %d samples, too few to split", class, len(rows))
+		}
+		rnd.Shuffle(len(rows), func(a, b int) { rows[a], rows[b] = rows[b], rows[a] })
+		for _, i := range rows[:nTest] {
+			test[i] = true
+		}
+	}
+
+	var trainRows, testRows []int
+	for i := 0; i < r; i++ {
+		if test[i] {
+			testRows = append(testRows, i)
+		} else {
+			trainRows = append(trainRows, i)
+		}
+	}
+	subset := func(rows []int) (*mat.Dense, []int) {
+		m := mat.NewDense(len(rows), c, nil)
+		labels := make([]int, len(rows))
+		row := make([]float64, c)
+		for i, idx := range rows {
+			m.SetRow(i, mat.Row(row, idx, x))
+			labels[i] = y[idx]
+		}
+		return m, labels
+	}
+	xTrain, yTrain = subset(trainRows)
+	xTest, yTest = subset(testRows)
+	return xTrain, xTest, yTrain, yTest, nil
+}
+
+// ConfusionMatrix counts the predictions for each pair of true and predicted
+// classes.
+//
+// Parameter predicted holds the predicted class of each sample.
+// Parameter actual holds the true class of each sample.
+// Parameter k is the number of classes.
+// Returns a k×k matrix whose entry [i][j] is the number of samples of true
+// class i predicted as class j.
+func ConfusionMatrix(predicted, actual []int, k int) ([][]int, error) {
+	if len(predicted) != len(actual) {
+		return nil, fmt.Errorf("Got %d predictions for %d samples", len(predicted), len(actual))
+	}
+	if k < 1 {
+		return nil, fmt.Errorf("Invalid number of classes %d", k)
+	}
+	matrix := make([][]int, k)
+	for i := range matrix {
+		matrix[i] = make([]int, k)
+	}
+	for i := range actual {
+		if actual[i] < 0 || actual[i] >= k {
+			return nil, fmt.Errorf("Invalid class label %d", actual[i])
+		}
+		if predicted[i] < 0 || predicted[i] >= k {
+			return nil, fmt.Errorf("Invalid class label %d", predicted[i])
+		}
+		matrix[actual[i]][predicted[i]]++
+	}
+	return matrix, nil
+}
+
+// Accuracy computes the proportion of predictions that match the true class.
+//
+// Parameter predicted holds the predicted class of each sample.
+// Parameter actual holds the true class of each sample.
+func Accuracy(predicted, actual []int) (float64, error) {
+	if len(predicted) != len(actual) {
+		return 0, fmt.Errorf("Got %d predictions for %d samples", len(predicted), len(actual))
+	}
+	if len(actual) == 0 {
+		return 0, fmt.Errorf("No samples")
+	}
+	var correct int
+	for i := range actual {
+		if predicted[i] == actual[i] {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(actual)), nil
+}
+
+// Score predicts the class of every row of x and returns the proportion of
+// predictions that match y, the classification accuracy on a labeled test
+// set.
+//
+// Parameter x is the r × p matrix of test data.
+// Parameter y holds the true class of each row of x.
+func (ld *LD) Score(x mat.Matrix, y []int) (float64, error) {
+	r, _ := x.Dims()
+	if len(y) != r {
+		return 0, fmt.Errorf("The sizes of X and Y don't match")
+	}
+	predicted, err := ld.PredictBatch(x)
+	if err != nil {
+		return 0, err
+	}
+	return Accuracy(predicted, y)
+}
+
+// ROCAUC computes the area under the ROC curve of a two-class problem from
+// the rank sum of the scores of the positive samples (the Mann-Whitney U
+// statistic). It is the probability that a random positive sample scores
+// higher than a random negative one, counting ties as one half.
+//
+// Parameter scores holds the score of each sample for the positive class,
+// such as its DecisionFunction score or PredictProba probability.
+// Parameter actual holds the true class of each sample, 1 for positive and
+// 0 for negative. Both classes must be present.
+func ROCAUC(scores []float64, actual []int) (auc float64, err error) {
+	if len(scores) != len(actual) {
+		return 0, fmt.Errorf("Got %d scores for %d samples", len(scores), len(actual))
+	}
+	var pos, neg int
+	for _, label := range actual {
+		switch label {
+		case 0:
+			neg++
+		case 1:
+			pos++
+		default:
+			return 0, fmt.Errorf("Invalid class label %d, want 0 or 1", label)
+		}
+	}
+	if pos == 0 || neg == 0 {
+		return 0, fmt.Errorf("Need samples of both classes, got %d positive and %d negative", pos, neg)
+	}
+	for i, v := range scores {
+		if math.IsNaN(v) {
+			return 0, fmt.Errorf("Score %d is NaN", i)
+		}
+	}
+
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return scores[order[a]] < scores[order[b]]
+	})
+	// Tied scores share the mean of their ranks
+	var rankSum float64
+	for i := 0; i < len(order); {
+		j := i
+		for j < len(order) && scores[order[j]] == scores[order[i]] {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for _, o := range order[i:j] {
+			if actual[o] == 1 {
+				rankSum += rank
+			}
+		}
+		i = j
+	}
+	u := rankSum - float64(pos)*float64(pos+1)/2
+	return u / (float64(pos) * float64(neg)), nil
+}
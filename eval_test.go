@@ -0,0 +1,259 @@
+package lda
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestTotalCost(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ld.TotalCost(dataMatrix, labels, mat.NewDense(2, 2, nil)); err == nil {
+		t.Error("expected an error for a cost matrix of the wrong size")
+	}
+	if _, err := ld.TotalCost(dataMatrix, labels[1:], mat.NewDense(3, 3, nil)); err == nil {
+		t.Error("expected an error for mismatched labels")
+	}
+
+	// Unit cost for every misclassification
+	cost := mat.NewDense(3, 3, []float64{
+		0, 1, 1,
+		1, 0, 1,
+		1, 1, 0,
+	})
+	got, err := ld.TotalCost(dataMatrix, labels, cost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var errors int
+	r, _ := dataMatrix.Dims()
+	for i := 0; i < r; i++ {
+		c, _ := ld.Predict(dataMatrix.RawRowView(i))
+		if c != labels[i] {
+			errors++
+		}
+	}
+	if got != float64(errors) {
+		t.Errorf("unexpected total cost got:%v, want:%d", got, errors)
+	}
+}
+
+func TestCrossValidate(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	for _, folds := range []int{1, 51} {
+		if _, err := CrossValidate(dataMatrix, labels, folds); err == nil {
+			t.Errorf("expected an error for %d folds", folds)
+		}
+	}
+	if _, err := CrossValidate(dataMatrix, labels[1:], 5); err == nil {
+		t.Error("expected an error for mismatched labels")
+	}
+
+	accuracy, err := CrossValidate(dataMatrix, labels, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accuracy <= 0.9 || accuracy > 1 {
+		t.Errorf("unexpected 5-fold accuracy got:%v, want >0.9", accuracy)
+	}
+}
+
+func TestConfusionMatrix(t *testing.T) {
+	if _, err := ConfusionMatrix([]int{0, 1}, []int{0}, 2); err == nil {
+		t.Error("expected an error for mismatched lengths")
+	}
+	if _, err := ConfusionMatrix([]int{0, 2}, []int{0, 1}, 2); err == nil {
+		t.Error("expected an error for a label outside [0,k)")
+	}
+	if _, err := Accuracy([]int{0}, []int{0, 1}); err == nil {
+		t.Error("expected an error for mismatched lengths")
+	}
+
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	// Every fifth sample
+	var predicted, actual []int
+	for i := 0; i < len(labels); i += 5 {
+		c, err := ld.Predict(dataMatrix.RawRowView(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		predicted = append(predicted, c)
+		actual = append(actual, labels[i])
+	}
+	matrix, err := ConfusionMatrix(predicted, actual, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total, correct int
+	for i := range matrix {
+		for j, count := range matrix[i] {
+			total += count
+			if i == j {
+				correct += count
+			}
+		}
+	}
+	if total != len(actual) {
+		t.Errorf("unexpected total count got:%d, want:%d", total, len(actual))
+	}
+	accuracy, err := Accuracy(predicted, actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := float64(correct) / float64(total); accuracy != want {
+		t.Errorf("unexpected accuracy got:%v, want:%v", accuracy, want)
+	}
+	if accuracy < 0.9 {
+		t.Errorf("unexpected accuracy got:%v, want at least 0.9", accuracy)
+	}
+}
+
+func TestROCAUC(t *testing.T) {
+	// Two well-separated classes ranked by the probability of class 1
+	rnd := rand.New(rand.NewSource(1))
+	x, y := gaussianClasses(rnd, [][]float64{{0, 0}, {10, 10}}, []int{100, 100})
+	var ld LD
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	r, _ := x.Dims()
+	scores := make([]float64, r)
+	for i := 0; i < r; i++ {
+		prob, err := ld.PredictProba(x.RawRowView(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		scores[i] = prob[1]
+	}
+	if auc, err := ROCAUC(scores, y); err != nil {
+		t.Fatal(err)
+	} else if auc != 1 {
+		t.Errorf("unexpected AUC for separable classes got:%v, want:1", auc)
+	}
+
+	// Scores unrelated to the labels
+	scores = make([]float64, 10000)
+	y = make([]int, len(scores))
+	for i := range scores {
+		scores[i] = rnd.Float64()
+		y[i] = rnd.Intn(2)
+	}
+	if auc, err := ROCAUC(scores, y); err != nil {
+		t.Fatal(err)
+	} else if math.Abs(auc-0.5) > 0.02 {
+		t.Errorf("unexpected AUC for random scores got:%v, want:~0.5", auc)
+	}
+
+	// Ties count as one half
+	if auc, _ := ROCAUC([]float64{1, 1, 1, 1}, []int{0, 1, 0, 1}); auc != 0.5 {
+		t.Errorf("unexpected AUC for tied scores got:%v, want:0.5", auc)
+	}
+
+	if _, err := ROCAUC([]float64{0.1, 0.2, 0.3}, []int{0, 1, 2}); err == nil {
+		t.Error("expected an error for three classes")
+	}
+	if _, err := ROCAUC([]float64{0.1, 0.2}, []int{1, 1}); err == nil {
+		t.Error("expected an error for one class")
+	}
+	if _, err := ROCAUC([]float64{0.1}, []int{0, 1}); err == nil {
+		t.Error("expected an error for mismatched lengths")
+	}
+}
+
+func TestScore(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if _, err := ld.Score(dataMatrix, labels); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	score, err := ld.Score(dataMatrix, labels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score <= 0.97 {
+		t.Errorf("unexpected accuracy got:%v, want above 0.97", score)
+	}
+	if _, err := ld.Score(dataMatrix, labels[1:]); err == nil {
+		t.Error("expected an error for mismatched labels")
+	}
+	if _, err := ld.Score(mat.NewDense(2, 3, nil), []int{0, 1}); err == nil {
+		t.Error("expected an error for a 3-column matrix")
+	}
+}
+
+func TestTrainTestSplit(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	xTrain, xTest, yTrain, yTest, err := TrainTestSplit(dataMatrix, labels, 0.2, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, c := xTrain.Dims(); r != 120 || c != 4 || len(yTrain) != 120 {
+		t.Errorf("unexpected training set size got:%d×%d with %d labels, want:120×4", r, c, len(yTrain))
+	}
+	if r, c := xTest.Dims(); r != 30 || c != 4 || len(yTest) != 30 {
+		t.Errorf("unexpected test set size got:%d×%d with %d labels, want:30×4", r, c, len(yTest))
+	}
+	// Each class keeps its third of both sets
+	for _, set := range []struct {
+		labels []int
+		want   int
+	}{{yTrain, 40}, {yTest, 10}} {
+		counts := make([]int, 3)
+		for _, label := range set.labels {
+			counts[label]++
+		}
+		for class, n := range counts {
+			if n != set.want {
+				t.Errorf("unexpected count of class %d got:%d, want:%d", class, n, set.want)
+			}
+		}
+	}
+
+	xTrain2, xTest2, yTrain2, yTest2, err := TrainTestSplit(dataMatrix, labels, 0.2, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mat.Equal(xTrain, xTrain2) || !mat.Equal(xTest, xTest2) {
+		t.Error("splits with the same seed differ")
+	}
+	for i := range yTest {
+		if yTest[i] != yTest2[i] {
+			t.Fatal("test labels with the same seed differ")
+		}
+	}
+	for i := range yTrain {
+		if yTrain[i] != yTrain2[i] {
+			t.Fatal("training labels with the same seed differ")
+		}
+	}
+	_, xOther, _, _, err := TrainTestSplit(dataMatrix, labels, 0.2, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mat.Equal(xTest, xOther) {
+		t.Error("expected a different seed to give a different split")
+	}
+
+	for _, frac := range []float64{0, 1, -0.5, math.NaN()} {
+		if _, _, _, _, err := TrainTestSplit(dataMatrix, labels, frac, 1); err == nil {
+			t.Errorf("expected an error for test fraction %v", frac)
+		}
+	}
+	small := mat.NewDense(4, 1, []float64{1, 2, 3, 4})
+	if _, _, _, _, err := TrainTestSplit(small, []int{0, 0, 0, 1}, 0.5, 1); err == nil {
+		t.Error("expected an error for a class with one sample")
+	}
+}
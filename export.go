@@ -0,0 +1,52 @@
+package lda
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// point2D is one row of a 2D projection written by ExportTransformedJSON.
+type point2D struct {
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Label int     `json:"label"`
+}
+
+// pointND is one row of a projection of any other dimension written by
+// ExportTransformedJSON.
+type pointND struct {
+	Coords []float64 `json:"coords"`
+	Label  int       `json:"label"`
+}
+
+// ExportTransformedJSON writes projected data and its labels to w as a JSON
+// array with one object per row, for plotting outside of Go. A 2D row is
+// written as {"x":..,"y":..,"label":..}, and a row of any other dimension
+// as {"coords":[..],"label":..}.
+//
+// Parameter projected is the r × n matrix returned by Transform.
+// Parameter labels holds the class of each row of projected.
+func ExportTransformedJSON(w io.Writer, projected *mat.Dense, labels []int) error {
+	r, c := projected.Dims()
+	if len(labels) != r {
+		return fmt.Errorf("Got %d labels for %d rows", len(labels), r)
+	}
+	var points interface{}
+	if c == 2 {
+		rows := make([]point2D, r)
+		for i := range rows {
+			rows[i] = point2D{X: projected.At(i, 0), Y: projected.At(i, 1), Label: labels[i]}
+		}
+		points = rows
+	} else {
+		rows := make([]pointND, r)
+		for i := range rows {
+			rows[i] = pointND{Coords: mat.Row(nil, i, projected), Label: labels[i]}
+		}
+		points = rows
+	}
+	return json.NewEncoder(w).Encode(points)
+}
@@ -0,0 +1,65 @@
+package lda
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestExportTransformedJSON(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	projected, err := ld.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := ExportTransformedJSON(&buf, projected, labels); err != nil {
+		t.Fatal(err)
+	}
+	var points []struct {
+		X, Y  float64
+		Label int
+	}
+	if err := json.Unmarshal(buf.Bytes(), &points); err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 150 {
+		t.Fatalf("unexpected number of records got:%d, want:150", len(points))
+	}
+	for i, pt := range points {
+		if pt.Label != labels[i] || pt.Label < 0 || pt.Label > 2 {
+			t.Errorf("unexpected label of record %d got:%d, want:%d", i, pt.Label, labels[i])
+		}
+		if pt.X != projected.At(i, 0) || pt.Y != projected.At(i, 1) {
+			t.Errorf("unexpected coordinates of record %d got:(%v,%v)", i, pt.X, pt.Y)
+		}
+	}
+
+	// Other dimensions use a coords array
+	one, err := ld.Transform(dataMatrix, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := ExportTransformedJSON(&buf, one, labels); err != nil {
+		t.Fatal(err)
+	}
+	var rows []struct {
+		Coords []float64
+		Label  int
+	}
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 150 || len(rows[0].Coords) != 1 || rows[0].Coords[0] != one.At(0, 0) {
+		t.Errorf("unexpected 1D export got:%v", rows[0])
+	}
+
+	if err := ExportTransformedJSON(&buf, projected, labels[:10]); err == nil {
+		t.Error("expected an error for mismatched labels")
+	}
+}
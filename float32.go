@@ -0,0 +1,62 @@
+package lda
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// Dense32 is a read-only row-major matrix of float32 values. It implements
+// mat.Matrix, so it can be passed to LinearDiscriminant, Transform and the
+// other methods that take a matrix without converting the whole data set to
+// float64. Values are converted one at a time as they are read, and all
+// accumulation is done in float64.
+type Dense32 struct {
+	rows, cols int
+	data       []float32
+}
+
+// NewDense32 creates a rows×cols matrix backed by data, which is used
+// directly and holds the elements in row-major order. NewDense32 panics if
+// the length of data is not rows*cols.
+func NewDense32(rows, cols int, data []float32) *Dense32 {
+	if rows <= 0 || cols <= 0 {
+		panic(mat.ErrZeroLength)
+	}
+	if len(data) != rows*cols {
+		panic(mat.ErrShape)
+	}
+	return &Dense32{rows: rows, cols: cols, data: data}
+}
+
+// Dims returns the number of rows and columns of the matrix.
+func (m *Dense32) Dims() (r, c int) {
+	return m.rows, m.cols
+}
+
+// At returns the element at row i and column j as a float64.
+func (m *Dense32) At(i, j int) float64 {
+	if uint(i) >= uint(m.rows) {
+		panic(mat.ErrRowAccess)
+	}
+	if uint(j) >= uint(m.cols) {
+		panic(mat.ErrColAccess)
+	}
+	return float64(m.data[i*m.cols+j])
+}
+
+// T returns the transpose of the matrix.
+func (m *Dense32) T() mat.Matrix {
+	return mat.Transpose{Matrix: m}
+}
+
+// DenseFromFloat32 copies row-major float32 data into a new rows×cols
+// mat.Dense. Use Dense32 instead to avoid the float64 copy.
+func DenseFromFloat32(rows, cols int, data []float32) *mat.Dense {
+	if len(data) != rows*cols {
+		panic(mat.ErrShape)
+	}
+	out := make([]float64, len(data))
+	for i, v := range data {
+		out[i] = float64(v)
+	}
+	return mat.NewDense(rows, cols, out)
+}
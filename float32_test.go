@@ -0,0 +1,51 @@
+package lda
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestDense32(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	r, c := dataMatrix.Dims()
+	data := make([]float32, 0, r*c)
+	for i := 0; i < r; i++ {
+		for _, v := range dataMatrix.RawRowView(i) {
+			data = append(data, float32(v))
+		}
+	}
+
+	var want LD
+	if err := want.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	for name, x := range map[string]mat.Matrix{
+		"Dense32":          NewDense32(r, c, data),
+		"DenseFromFloat32": DenseFromFloat32(r, c, data),
+	} {
+		var ld LD
+		if err := ld.LinearDiscriminant(x, labels); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		got, wantValues := ld.EigenValues(), want.EigenValues()
+		for i := 0; i < 2; i++ {
+			if math.Abs(got[i]-wantValues[i]) > 1e-4*wantValues[i] {
+				t.Errorf("%s: unexpected eigenvalue %d got:%v, want:%v", name, i, got[i], wantValues[i])
+			}
+		}
+		for i := 0; i < r; i++ {
+			g, _ := ld.Predict(dataMatrix.RawRowView(i))
+			w, _ := want.Predict(dataMatrix.RawRowView(i))
+			if g != w {
+				t.Errorf("%s: unexpected prediction for row %d got:%d, want:%d", name, i, g, w)
+			}
+		}
+	}
+
+	m := NewDense32(2, 3, []float32{1, 2, 3, 4, 5, 6})
+	if m.At(1, 0) != 4 || m.T().At(2, 1) != 6 {
+		t.Errorf("unexpected elements of %v", mat.Formatted(m))
+	}
+}
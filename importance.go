@@ -0,0 +1,91 @@
+package lda
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ScaleInvariantImportance ranks the features by their discriminative power
+// in a way that doesn't change when a feature is rescaled.
+//
+// The importance of feature j is
+//
+//	F_j * Σ_c w_c |s_jc|
+//
+// where F_j = (Cb_jj/(k-1)) / (Cw_jj/(n-k)) is the univariate F-statistic of
+// the feature, s_c is the c-th discriminant eigenvector with each entry
+// multiplied by the within-class standard deviation sqrt(Cw_jj) and then
+// normalized to unit length, and w_c is the eigenvalue of the c-th
+// discriminant divided by the sum over the k-1 discriminants.
+// Returns a slice with one entry per feature, or nil if the model has not
+// been fit.
+func (ld *LD) ScaleInvariantImportance() []float64 {
+	if !ld.ok {
+		return nil
+	}
+	m := ld.k - 1
+	if m > ld.p {
+		m = ld.p
+	}
+	order := discriminantOrder(ld.evals)[:m]
+
+	var total float64
+	for _, c := range order {
+		total += cmplx.Abs(ld.evals[c])
+	}
+	importance := make([]float64, ld.p)
+	s := make([]float64, ld.p)
+	for _, c := range order {
+		var norm float64
+		for j := 0; j < ld.p; j++ {
+			s[j] = ld.evecs.At(j, c) * math.Sqrt(ld.cw.At(j, j))
+			norm += s[j] * s[j]
+		}
+		norm = math.Sqrt(norm)
+		w := cmplx.Abs(ld.evals[c]) / total
+		for j := 0; j < ld.p; j++ {
+			importance[j] += w * math.Abs(s[j]) / norm
+		}
+	}
+	for j := 0; j < ld.p; j++ {
+		f := (ld.cb.At(j, j) / float64(ld.k-1)) / (ld.cw.At(j, j) / float64(ld.n-ld.k))
+		importance[j] *= f
+	}
+	return importance
+}
+
+// FeatureLoadings returns a copy of the p×m matrix of discriminant
+// eigenvectors, where m is the number of discriminants. Entry [j][c] is the
+// loading of feature j on component c, in the order of the components
+// returned by Transform, with the sign of each eigenvector normalized so
+// that its largest entry is positive. It returns nil if the model has not
+// been fit.
+func (ld *LD) FeatureLoadings() *mat.Dense {
+	if ld.svecs == nil || len(ld.dirs) == 0 {
+		return nil
+	}
+	return mat.DenseCopyOf(ld.svecs.Slice(0, ld.p, 0, len(ld.dirs)))
+}
+
+// FeatureImportance scores each feature by the sum of its absolute loadings
+// on the discriminants, each weighted by the proportion of the between-class
+// variance that the discriminant explains. The loadings depend on the units
+// of the features, so the scores are only comparable between features on
+// similar scales; see ScaleInvariantImportance otherwise. It returns nil if
+// the model has not been fit.
+func (ld *LD) FeatureImportance() []float64 {
+	loadings := ld.FeatureLoadings()
+	if loadings == nil {
+		return nil
+	}
+	ratios := ld.ExplainedVarianceRatio()
+	importance := make([]float64, ld.p)
+	for j := range importance {
+		for c, w := range ratios {
+			importance[j] += w * math.Abs(loadings.At(j, c))
+		}
+	}
+	return importance
+}
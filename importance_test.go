@@ -0,0 +1,79 @@
+package lda
+
+import (
+	"sort"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// rank returns the indices of v ordered by decreasing value.
+func rank(v []float64) []int {
+	order := make([]int, len(v))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return v[order[a]] > v[order[b]] })
+	return order
+}
+
+func TestScaleInvariantImportance(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if ld.ScaleInvariantImportance() != nil {
+		t.Error("expected nil importance for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	want := rank(ld.ScaleInvariantImportance())
+
+	var scaled mat.Dense
+	scaled.CloneFrom(dataMatrix)
+	r, _ := scaled.Dims()
+	for i := 0; i < r; i++ {
+		scaled.Set(i, 2, 100*scaled.At(i, 2))
+	}
+	var scaledLD LD
+	if err := scaledLD.LinearDiscriminant(&scaled, labels); err != nil {
+		t.Fatal(err)
+	}
+	got := rank(scaledLD.ScaleInvariantImportance())
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ranking changed after rescaling got:%v, want:%v", got, want)
+		}
+	}
+}
+
+func TestFeatureImportance(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if ld.FeatureLoadings() != nil || ld.FeatureImportance() != nil {
+		t.Error("expected nil loadings and importance for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	loadings := ld.FeatureLoadings()
+	if r, c := loadings.Dims(); r != 4 || c != 2 {
+		t.Fatalf("unexpected dimensions got:%d×%d, want:4×2", r, c)
+	}
+	W, err := ld.ProjectionMatrix(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mat.Equal(loadings, W) {
+		t.Error("loadings differ from the projection matrix")
+	}
+
+	// Petal length (2) and petal width (3) dominate the Iris discriminants
+	importance := ld.FeatureImportance()
+	if len(importance) != 4 {
+		t.Fatalf("unexpected length got:%d, want:4", len(importance))
+	}
+	order := rank(importance)
+	if top := order[0] + order[1]; top != 5 {
+		t.Errorf("expected the petal features to rank first got:%v from %v", order, importance)
+	}
+}
@@ -6,10 +6,17 @@
 package lda
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"log"
 	"math"
 	"math/cmplx"
+	"math/rand"
+	"runtime"
 	"sort"
+	"strings"
+	"time"
 
 	"gonum.org/v1/gonum/mat"
 )
@@ -23,23 +30,184 @@ type LD struct {
 	ct    []float64  // Constant term of discriminant function of each class
 	mu    *mat.Dense // Mean vectors of each class
 	svd   *mat.SVD
-	ok    bool
-	eigen mat.Eigen //Eigen values of common variance matrix
+	ok    bool         // Set by a successful fit, see IsFitted
+	eigen mat.Eigen    //Eigen values of common variance matrix
+	evecs *mat.Dense   // Real parts of the right eigenvectors, cached after fitting
+	evals []complex128 // Eigenvalues, cached after fitting
+	wvar  []float64    // Within-class variance along each eigenvector, cached after fitting
+	dirs  []int        // Indices of the discriminant eigenvectors used by Predict, cached after fitting
+	svecs *mat.Dense   // Eigenvectors in decreasing order of eigenvalue magnitude, cached after fitting
+	imag  float64      // Largest imaginary part discarded from the eigensystem, see MaxImaginaryComponent
+	rproj *mat.Dense   // Whitened discriminant eigenvectors used by ReducedPredict, cached after fitting
+	rmu   *mat.Dense   // Class means projected by rproj, cached after fitting
+
+	tol     float64   // Tolerance for rejecting low-variance variables, see SetTol
+	priors  []float64 // Custom priori probability of each class, see SetPriors
+	weights []float64 // Weight of each class in the between-class scatter, see SetClassWeights
+	solver  Solver    // Method of solving the eigenvalue problem, see SetSolver
+	shrink  float64   // Shrinkage of the within-class scatter matrix, see SetShrinkage
+	ridge   float64   // Ridge added to the within-class covariance, see SetRidge
+	diag    bool      // Use only the diagonal of the within-class scatter, see SetDiagonalCovariance
+
+	standardize   bool      // Z-score the features before fitting, see SetStandardize
+	center, scale []float64 // Mean and standard deviation of each feature, set when standardizing
+
+	logger *log.Logger // Destination of fit diagnostics, see SetDebug
+
+	ni   []int         // Number of instances in each class
+	cw   *mat.SymDense // Within-class scatter matrix, divided by (n-k) in solve
+	cb   *mat.Dense    // Between-class scatter matrix
+	mean []float64     // Common mean vector, subtracted by Transform
+
+	transformOnly bool // Fit by FitTransformOnly, without the classifier state
+
+	classes []string // Original string labels by class index, set by FitLabels
+	labels  []int    // Original int labels by class index, set by FitRemap
+
+	scoreMean []float64 // Mean of each class's score over the training data
+	scoreStd  []float64 // Standard deviation of each class's score over the training data
+
+	partial *partialStats // Statistics accumulated by PartialFit until Finalize
 }
 
 // LinearDiscriminant performs linear discriminant analysis on the
 // matrix of the input data, which is represented as an n×p matrix x,
 // where each row is an observation and each column is a variable.
 //
+// The discriminants are found from the pooled within-class covariance
+// matrix, the within-class scatter matrix divided by (n-k), so the
+// eigenvalues do not grow with the number of samples.
 //
 // Parameter x is a matrix of input/training data.
 // Parameter y is an array of input/training labels in [0,k)
-// where k is the number of classes.
+// where k is the number of classes. k is inferred as one more than the largest
+// label, so every class in [0,k) must have at least one sample; a gap in the
+// labels is reported as an error naming the first missing class.
 // Returns true iff the analysis was successful.
-func (ld *LD) LinearDiscriminant(x mat.Matrix, y []int) (err error) {
+func (ld *LD) LinearDiscriminant(x mat.Matrix, y []int) error {
+	return ld.LinearDiscriminantContext(context.Background(), x, y)
+}
+
+// LinearDiscriminantContext performs the same analysis as LinearDiscriminant,
+// but stops early and returns ctx.Err() if ctx is cancelled, for example when
+// fitting a very large matrix takes longer than a request deadline. The
+// context is checked before any work is done, periodically while the input
+// is validated and the scatter matrix computed, and between the steps of the
+// fit. A cancelled fit leaves the model unfitted.
+func (ld *LD) LinearDiscriminantContext(ctx context.Context, x mat.Matrix, y []int) error {
+	return ld.fit(ctx, x, y, true)
+}
+
+// fit performs the analysis for LinearDiscriminantContext and
+// FitTransformOnly. Without classifier, the constant terms and the score
+// statistics are skipped and the model is marked as transform only.
+func (ld *LD) fit(ctx context.Context, x mat.Matrix, y []int, classifier bool) (err error) {
+	// A failed fit leaves the model unfitted rather than half-populated
+	ld.Reset()
+	ld.transformOnly = !classifier
+	defer func() {
+		if err != nil {
+			ld.Reset()
+		}
+	}()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	ld.n, ld.p = x.Dims()
-	if y != nil && len(y) != ld.n {
-		return fmt.Errorf("The sizes of X and Y don't match")
+	if ld.k, err = classLabels(y, ld.n); err != nil {
+		return err
+	}
+	if err := checkFinite(ctx, x); err != nil {
+		return err
+	}
+
+	// Tol is a tolerence to decide if a covariance matrix is singular (det is zero)
+	// Tol will reject variables whose variance is less than tol
+	var tol = ld.tolerance()
+
+	if ld.k < 2 {
+		return fmt.Errorf("Only one class")
+	}
+	if tol < 0.0 {
+		return fmt.Errorf("Invalid tol")
+	}
+	if err := checkClassSizes(classSizes(y, ld.k)); err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// The score statistics are computed from the original data, which
+	// DecisionFunction standardizes itself
+	raw := x
+	if ld.standardize {
+		if x, err = ld.standardizeFit(x); err != nil {
+			return err
+		}
+	}
+
+	// Common mean vector
+	var colmean []float64
+	for i := 0; i < ld.p; i++ {
+		var col = mat.Col(nil, i, x)
+		var sum float64
+		for _, value := range col {
+			sum += value
+		}
+		colmean = append(colmean, sum/float64(ld.n))
+	}
+
+	// Class mean vectors and number of instances in each class
+	mu, ni, err := classMeans(x, y, ld.k)
+	if err != nil {
+		return err
+	}
+	ld.mu = mu
+	ld.ni = ni
+	if err := ld.checkClassWeights(); err != nil {
+		return err
+	}
+	if classifier {
+		if err := ld.setConstants(); err != nil {
+			return err
+		}
+	}
+
+	// Calculate covariance matrix in 2 steps
+
+	// Step 1: calculate within-class scatter matrix
+	Cw, err := withinScatterParallel(ctx, x, y, ld.mu, runtime.NumCPU())
+	if err != nil {
+		return err
+	}
+
+	// Step 2: calculate between-class scatter matrix
+	ld.cw = Cw
+	ld.cb = ld.betweenScatter(colmean)
+	ld.mean = colmean
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := ld.solve(); err != nil {
+		return err
+	}
+	// The score statistics are computed with DecisionFunction, which needs
+	// a fitted model
+	ld.ok = true
+	if !classifier {
+		return nil
+	}
+	return ld.setScoreStatistics(raw)
+}
+
+// classLabels validates the labels y of n observations and returns the
+// number of classes k. The labels must cover [0,k) without gaps.
+func classLabels(y []int, n int) (int, error) {
+	if y != nil && len(y) != n {
+		return 0, fmt.Errorf("The sizes of X and Y don't match")
 	}
 	var labels []int
 	var labelMap = map[int]int{}
@@ -57,69 +225,112 @@ func (ld *LD) LinearDiscriminant(x mat.Matrix, y []int) (err error) {
 	sort.Ints(labels)
 
 	if len(labels) == 0 {
-		return fmt.Errorf("No data to analyze")
+		return 0, fmt.Errorf("No data to analyze")
 	}
 	if labels[0] != 0 {
-		return fmt.Errorf("Label does not start from zero")
+		return 0, fmt.Errorf("Label does not start from zero")
 	}
 	for i := 0; i < len(labels); i++ {
 		if labels[i] < 0 {
-			return fmt.Errorf("Negative class label")
+			return 0, fmt.Errorf("Negative class label")
 		}
 		if i > 0 && labels[i]-labels[i-1] > 1 {
-			return fmt.Errorf("Missing class")
+			return 0, fmt.Errorf("Missing class %d", labels[i-1]+1)
 		}
 	}
+	return len(labels), nil
+}
 
-	// Tol is a tolerence to decide if a covariance matrix is singular (det is zero)
-	// Tol will reject variables whose variance is less than tol
-	var tol = 1e-4
-
-	ld.k = len(labels)
-	if ld.k < 2 {
-		return fmt.Errorf("Only one class")
-	}
-	if tol < 0.0 {
-		return fmt.Errorf("Invalid tol")
-	}
-	if ld.n <= ld.k {
-		return fmt.Errorf("Sample size is too small")
+// classSizes returns the number of labels in y of each class in [0,k).
+func classSizes(y []int, k int) []int {
+	counts := make([]int, k)
+	for _, label := range y {
+		counts[label]++
 	}
+	return counts
+}
 
-	// Number of instances in each class
-	ni := make([]int, ld.k)
-
-	// Common mean vector
-	var colmean []float64
-	for i := 0; i < ld.p; i++ {
-		var col = mat.Col(nil, i, x)
-		var sum float64
-		for _, value := range col {
-			sum += value
+// checkClassSizes returns an error naming the first class with fewer than
+// two samples, the fewest that give an estimate of its within-class
+// variance.
+func checkClassSizes(counts []int) error {
+	for c, count := range counts {
+		switch count {
+		case 0:
+			return fmt.Errorf("Class %d has no samples", c)
+		case 1:
+			return fmt.Errorf("Class %d has only one sample, at least 2 per class are needed to estimate the within-class variance", c)
 		}
-		colmean = append(colmean, sum/float64(ld.n))
 	}
+	return nil
+}
 
-	// Class mean vectors
-	// mu is a k x ld.p matrix
-	ld.mu = mat.NewDense(ld.k, ld.p, make([]float64, ld.k*ld.p, ld.k*ld.p))
-	for i := 0; i < ld.n; i++ {
+// classMeans computes the k×p matrix of class mean vectors of x, one row per
+// class, and the number of instances in each class.
+func classMeans(x mat.Matrix, y []int, k int) (*mat.Dense, []int, error) {
+	n, p := x.Dims()
+	ni := make([]int, k)
+	mu := mat.NewDense(k, p, make([]float64, k*p, k*p))
+	for i := 0; i < n; i++ {
 		ni[y[i]]++
-		for j := 0; j < ld.p; j++ {
-			ld.mu.Set(y[i], j, ((ld.mu.At(y[i], j)) + (x.At(i, j))))
+		for j := 0; j < p; j++ {
+			mu.Set(y[i], j, ((mu.At(y[i], j)) + (x.At(i, j))))
 		}
 	}
 
-	for i := 0; i < ld.k; i++ {
-		for j := 0; j < ld.p; j++ {
-			ld.mu.Set(i, j, ((ld.mu.At(i, j)) / (float64)(ni[i])))
+	// An empty class would make its mean and prior NaN
+	for i, count := range ni {
+		if count == 0 {
+			return nil, nil, fmt.Errorf("Class %d has no samples", i)
 		}
 	}
 
+	for i := 0; i < k; i++ {
+		for j := 0; j < p; j++ {
+			mu.Set(i, j, ((mu.At(i, j)) / (float64)(ni[i])))
+		}
+	}
+	return mu, ni, nil
+}
+
+// Reset clears the fitted model, and any statistics accumulated by
+// PartialFit, so the same LD can be fit again from scratch. Settings such as
+// the tolerance, priors, class weights, solver, shrinkage, ridge, diagonal
+// covariance and standardization are kept.
+func (ld *LD) Reset() {
+	*ld = ld.settings()
+}
+
+// settings returns an unfitted LD with the settings of ld, for fitting
+// other models the same way ld is fit.
+func (ld *LD) settings() LD {
+	return LD{
+		tol:         ld.tol,
+		priors:      ld.priors,
+		weights:     ld.weights,
+		solver:      ld.solver,
+		shrink:      ld.shrink,
+		ridge:       ld.ridge,
+		diag:        ld.diag,
+		logger:      ld.logger,
+		standardize: ld.standardize,
+	}
+}
+
+// setConstants computes the constant term of the discriminant function of
+// each class from the custom priors, or from the class frequencies if no
+// priors were set.
+func (ld *LD) setConstants() error {
 	// priori is the priori probability of each class
 	priori := make([]float64, ld.k)
 	for i := 0; i < ld.k; i++ {
-		priori[i] = float64(ni[i]) / float64(ld.n)
+		priori[i] = float64(ld.ni[i]) / float64(ld.n)
+	}
+	if ld.priors != nil {
+		if len(ld.priors) != ld.k {
+			return fmt.Errorf("Got %d priors for %d classes", len(ld.priors), ld.k)
+		}
+		priori = ld.priors
 	}
 
 	// ct is the constant term of discriminant function of each class
@@ -127,47 +338,764 @@ func (ld *LD) LinearDiscriminant(x mat.Matrix, y []int) (err error) {
 	for i := 0; i < ld.k; i++ {
 		ld.ct[i] = math.Log(priori[i])
 	}
+	return nil
+}
 
-	// Calculate covariance matrix in 2 steps
+// checkClassWeights checks that any class weights set by SetClassWeights
+// match the number of classes, before betweenScatter uses them.
+func (ld *LD) checkClassWeights() error {
+	if ld.weights != nil && len(ld.weights) != ld.k {
+		return fmt.Errorf("Got %d class weights for %d classes", len(ld.weights), ld.k)
+	}
+	return nil
+}
 
-	// Step 1: calculate within-class scatter matrix
-	// Cw is the within-class scatter matrix initialized as a ld.p x ld.p zero matrix
-	Cw := mat.NewSymDense(ld.p, make([]float64, ld.p*ld.p, ld.p*ld.p))
+// betweenScatter calculates the between-class scatter matrix from the class
+// means and the common mean vector colmean. If class weights are set, each
+// class contributes in proportion to its weight times its size, around the
+// mean weighted the same way.
+func (ld *LD) betweenScatter(colmean []float64) *mat.Dense {
+	// Cb is the between-class scatter matrix initialized as a ld.p x ld.p zero matrix
+	Cb := mat.NewDense(ld.p, ld.p, make([]float64, ld.p*ld.p, ld.p*ld.p))
 
-	for i := 0; i < ld.n; i++ {
-		for j := 0; j < ld.p; j++ {
-			for l := 0; l <= j; l++ {
-				Cw.SetSym(j, l, (Cw.At(j, l) + ((x.At(i, j) - ld.mu.At(y[i], j)) * (x.At(i, l) - ld.mu.At(y[i], l)))))
+	if ld.weights != nil {
+		colmean = make([]float64, ld.p)
+		var total float64
+		for i := 0; i < ld.k; i++ {
+			w := ld.weights[i] * float64(ld.ni[i])
+			for j := 0; j < ld.p; j++ {
+				colmean[j] += w * ld.mu.At(i, j)
 			}
+			total += w
+		}
+		for j := range colmean {
+			colmean[j] /= total
 		}
 	}
-	tol = tol * tol
-
-	// Step 2: calculate between-class scatter matrix
-	// Cb is the between-class scatter matrix initialized as a ld.p x ld.p zero matrix
-	Cb := mat.NewDense(ld.p, ld.p, make([]float64, ld.p*ld.p, ld.p*ld.p))
 
 	for i := 0; i < ld.k; i++ {
-		n := float64(labelMap[i])
+		n := float64(ld.ni[i])
+		if ld.weights != nil {
+			n *= ld.weights[i]
+		}
 		for j := 0; j < ld.p; j++ {
 			for l := 0; l < ld.p; l++ {
 				Cb.Set(j, l, (Cb.At(j, l) + n*((ld.mu.At(i, j)-colmean[j])*(ld.mu.At(i, l)-colmean[l]))))
 			}
 		}
 	}
+	return Cb
+}
+
+// solve checks the pooled within-class covariance matrix for variables with
+// too little variance and solves the generalized eigenvalue problem for it
+// and the stored between-class scatter matrix.
+//
+// The within-class scatter matrix is divided by (n-k) to form the pooled
+// covariance matrix, so the eigenvalues are (n-k) times those of the raw
+// scatter matrices while the eigenvectors are unchanged.
+func (ld *LD) solve() error {
+	Cw, Cb := ld.pooledCovariance(), ld.cb
+	tol := ld.tolerance()
+	tol = tol * tol
+	for j := 0; j < ld.p; j++ {
+		if Cw.At(j, j) < tol {
+			return fmt.Errorf("Covariance matrix (column %d) is close to singular", j)
+		}
+	}
+
+	Cw = ld.regularize(Cw)
 
 	// Solving generalized eigenvalue problem for the matrix
-	CwInverse := mat.NewDense(ld.p, ld.p, make([]float64, ld.p*ld.p, ld.p*ld.p))
-	CwInverse.Inverse(Cw)
+	var err error
+	switch {
+	case ld.p == 1:
+		// With a single feature the problem is scalar: the projection is
+		// the identity and the eigenvalue is the ratio of the scatters
+		ld.eigen = mat.Eigen{}
+		ld.evecs = mat.NewDense(1, 1, []float64{1})
+		ld.evals = []complex128{complex(Cb.At(0, 0)/Cw.At(0, 0), 0)}
+	case ld.solver == CholeskySolver:
+		err = ld.symmetricEigen(Cw, Cb)
+	default:
+		err = ld.inverseEigen(Cw, Cb, tol)
+	}
+	if err != nil {
+		return err
+	}
+	normalizeSigns(ld.evecs)
+	if ld.logger != nil {
+		ld.logger.Printf("class counts %v", ld.ni)
+		ld.logger.Printf("condition number of the within-class covariance %.6g", mat.Cond(Cw, 2))
+		ld.logger.Printf("eigenvalues %v", ld.evals)
+	}
+	var max float64
+	for i, v := range ld.evals {
+		if cmplx.IsNaN(v) || cmplx.IsInf(v) {
+			return fmt.Errorf("Eigenvalue %d is not finite", i)
+		}
+		max = math.Max(max, cmplx.Abs(v))
+	}
+	// Eigenvalues that are zero up to rounding error are set to exactly zero,
+	// so their noise doesn't dominate the discriminant scores.
+	for i, v := range ld.evals {
+		if cmplx.Abs(v) < zeroEigenvalueTol*max {
+			ld.evals[i] = 0
+		}
+	}
+
+	// The discriminant scores measure distances along each eigenvector in
+	// units of its within-class standard deviation.
+	ld.wvar = make([]float64, ld.p)
+	for j := 0; j < ld.p; j++ {
+		v := ld.evecs.ColView(j)
+		ld.wvar[j] = mat.Inner(v, Cw, v)
+	}
+
+	// Cb has rank at most k-1, so only the k-1 largest eigenvalues can be
+	// nonzero; the rest are rounding error whose sign and size vary between
+	// runs. Only the eigenvectors of the nonzero ones discriminate.
+	order := discriminantOrder(ld.evals)
+	ld.dirs = nil
+	for _, j := range order {
+		if len(ld.dirs) == ld.k-1 || ld.evals[j] == 0 {
+			break
+		}
+		ld.dirs = append(ld.dirs, j)
+	}
+
+	// Only the inverse solvers factorize a nonsymmetric matrix, whose
+	// eigensystem can come out complex. The eigenvectors of the zero
+	// eigenvalues are arbitrary, so only the discriminants are checked.
+	ld.imag = 0
+	if ld.p > 1 && ld.solver != CholeskySolver {
+		var complexVectors mat.CDense
+		ld.eigen.VectorsTo(&complexVectors)
+		ld.imag = maxImaginary(ld.evals, &complexVectors, ld.dirs)
+	}
+	if ld.logger != nil {
+		ld.logger.Printf("retained %d of %d discriminants", len(ld.dirs), ld.p)
+		ld.logger.Printf("largest imaginary component %.3g", ld.imag)
+	}
+
+	// Transform projects onto the leading columns of the sorted eigenvectors
+	ld.svecs = mat.NewDense(ld.p, ld.p, nil)
+	col := make([]float64, ld.p)
+	for c, j := range order {
+		ld.svecs.SetCol(c, mat.Col(col, j, ld.evecs))
+	}
+	ld.reduce()
+	return nil
+}
+
+// inverseEigen solves the generalized eigenvalue problem by inverting Cw,
+// or taking its pseudo-inverse with SVDSolver, and factorizing the
+// nonsymmetric matrix Cw⁻¹·Cb.
+func (ld *LD) inverseEigen(Cw mat.Symmetric, Cb mat.Matrix, tol float64) error {
+	var CwInverse *mat.Dense
+	switch ld.solver {
+	case SVDSolver:
+		var err error
+		CwInverse, err = ld.pseudoInverse(Cw, tol)
+		if err != nil {
+			return err
+		}
+	default:
+		err := collinearColumns(Cw)
+		if err == nil {
+			CwInverse = mat.NewDense(ld.p, ld.p, make([]float64, ld.p*ld.p, ld.p*ld.p))
+			err = CwInverse.Inverse(Cw)
+		}
+		// A singular matrix falls back to the pseudo-inverse, as if
+		// SVDSolver had been selected
+		if err != nil {
+			if ld.logger != nil {
+				ld.logger.Printf("within-class scatter matrix is singular: %v; falling back to the pseudo-inverse", err)
+			}
+			if CwInverse, err = ld.pseudoInverse(Cw, tol); err != nil {
+				return err
+			}
+		}
+	}
+	if !isFinite(CwInverse) {
+		return fmt.Errorf("Inverse of the within-class scatter matrix is not finite")
+	}
 	dotResult := mat.NewDense(ld.p, ld.p, make([]float64, ld.p*ld.p, ld.p*ld.p))
 	dotResult.Mul(CwInverse, Cb)
-	ld.eigen.Factorize(dotResult, mat.EigenRight)
+
+	// The eigendecomposition doesn't terminate on a matrix with NaN or
+	// infinite elements, which overflow in the product can produce
+	if !isFinite(dotResult) {
+		return fmt.Errorf("Matrix to factorize is not finite")
+	}
 
 	// Factorize returns whether the decomposition of the matrix into eigenvectors
-	// and eigenvalues succeeded.
-	// If the decomposition failed, methods that require a successful factorization will panic
-	evals := make([]complex128, ld.p)
-	ld.eigen.Values(evals)
+	// and eigenvalues succeeded. If it failed, methods that require a successful
+	// factorization would panic, so the fit fails instead.
+	if ok := ld.eigen.Factorize(dotResult, mat.EigenRight); !ok {
+		ld.eigen = mat.Eigen{}
+		return fmt.Errorf("Eigendecomposition of the scatter matrices failed")
+	}
+
+	// The eigenvectors and eigenvalues are cached so that Transform and Predict
+	// don't have to extract them from the factorization on every call.
+	ld.evecs = getRealVectors(&ld.eigen)
+	ld.evals = ld.eigen.Values(nil)
+	return nil
+}
+
+// maxImaginary returns the largest absolute imaginary part of the
+// eigenvalues and of the entries of the eigenvectors in the columns cols.
+func maxImaginary(evals []complex128, evecs mat.CMatrix, cols []int) float64 {
+	var max float64
+	for _, v := range evals {
+		max = math.Max(max, math.Abs(imag(v)))
+	}
+	r, _ := evecs.Dims()
+	for _, j := range cols {
+		for i := 0; i < r; i++ {
+			max = math.Max(max, math.Abs(imag(evecs.At(i, j))))
+		}
+	}
+	return max
+}
+
+// symmetricEigen solves the generalized eigenvalue problem Cb·v = λ·Cw·v
+// with CholeskySolver. With Cw = L·Lᵀ, the eigenvalues are those of the
+// symmetric matrix L⁻¹·Cb·L⁻ᵀ, and each eigenvector w of it gives v = L⁻ᵀ·w,
+// scaled to unit length like the eigenvectors of the other solvers.
+func (ld *LD) symmetricEigen(Cw mat.Symmetric, Cb mat.Matrix) error {
+	ld.eigen = mat.Eigen{}
+	var chol mat.Cholesky
+	if ok := chol.Factorize(Cw); !ok {
+		if err := collinearColumns(Cw); err != nil {
+			return fmt.Errorf("Within-class scatter matrix is singular: %v", err)
+		}
+		return fmt.Errorf("Within-class scatter matrix is not positive definite")
+	}
+	var L, Linv mat.TriDense
+	chol.LTo(&L)
+	if err := Linv.InverseTri(&L); err != nil {
+		return fmt.Errorf("Within-class scatter matrix is singular: %v", err)
+	}
+	var m mat.Dense
+	m.Product(&Linv, Cb, Linv.T())
+	// m is symmetric up to rounding error
+	sym := mat.NewSymDense(ld.p, nil)
+	for j := 0; j < ld.p; j++ {
+		for l := 0; l <= j; l++ {
+			sym.SetSym(j, l, (m.At(j, l)+m.At(l, j))/2)
+		}
+	}
+	if !isFinite(sym) {
+		return fmt.Errorf("Matrix to factorize is not finite")
+	}
+	var es mat.EigenSym
+	if ok := es.Factorize(sym, true); !ok {
+		return fmt.Errorf("Eigendecomposition of the scatter matrices failed")
+	}
+	var w mat.Dense
+	es.VectorsTo(&w)
+	ld.evecs = mat.NewDense(ld.p, ld.p, nil)
+	ld.evecs.Mul(Linv.T(), &w)
+	for j := 0; j < ld.p; j++ {
+		norm := mat.Norm(ld.evecs.ColView(j), 2)
+		for i := 0; i < ld.p; i++ {
+			ld.evecs.Set(i, j, ld.evecs.At(i, j)/norm)
+		}
+	}
+	values := es.Values(nil)
+	ld.evals = make([]complex128, ld.p)
+	for j, v := range values {
+		ld.evals[j] = complex(v, 0)
+	}
+	return nil
+}
+
+// zeroEigenvalueTol is the magnitude, relative to the largest eigenvalue,
+// below which an eigenvalue is treated as zero.
+const zeroEigenvalueTol = 1e-10
+
+// collinearTol is the fraction of a column's within-class variance, left
+// unexplained by the columns before it, below which the column is treated as
+// a linear combination of them.
+const collinearTol = 1e-10
+
+// collinearColumns returns an error naming the first column of the
+// covariance matrix cov that is a linear combination of the columns before
+// it, together with the columns it depends on, or nil if there is none.
+// The check regresses each column on the independent columns before it,
+// using the correlation matrix so that the scale of the columns doesn't
+// matter.
+func collinearColumns(cov mat.Symmetric) error {
+	p, _ := cov.Dims()
+	sd := make([]float64, p)
+	for j := range sd {
+		sd[j] = math.Sqrt(cov.At(j, j))
+	}
+	var idx []int
+	for j := 0; j < p; j++ {
+		if len(idx) == 0 {
+			idx = append(idx, j)
+			continue
+		}
+		// Correlations among the independent columns and with column j
+		r := mat.NewSymDense(len(idx), nil)
+		b := mat.NewVecDense(len(idx), nil)
+		for a, ia := range idx {
+			for c, ic := range idx[:a+1] {
+				r.SetSym(a, c, cov.At(ia, ic)/(sd[ia]*sd[ic]))
+			}
+			b.SetVec(a, cov.At(ia, j)/(sd[ia]*sd[j]))
+		}
+		var chol mat.Cholesky
+		if ok := chol.Factorize(r); !ok {
+			return fmt.Errorf("columns %v are collinear", idx)
+		}
+		var coef mat.VecDense
+		if err := chol.SolveVecTo(&coef, b); err != nil {
+			return err
+		}
+		if 1-mat.Dot(b, &coef) < collinearTol {
+			var deps []int
+			for a, i := range idx {
+				if math.Abs(coef.AtVec(a)) > math.Sqrt(collinearTol) {
+					deps = append(deps, i)
+				}
+			}
+			return fmt.Errorf("column %d is a linear combination of columns %v", j, deps)
+		}
+		idx = append(idx, j)
+	}
+	return nil
+}
+
+// checkFinite returns an error naming the first element of x that is NaN or
+// infinite, or nil if there is none. It returns ctx.Err() if ctx is
+// cancelled before every row is checked.
+func checkFinite(ctx context.Context, x mat.Matrix) error {
+	r, c := x.Dims()
+	for i := 0; i < r; i++ {
+		if i%cancelCheckRows == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		for j := 0; j < c; j++ {
+			if v := x.At(i, j); math.IsNaN(v) || math.IsInf(v, 0) {
+				return fmt.Errorf("Non-finite value %v at (%d, %d)", v, i, j)
+			}
+		}
+	}
+	return nil
+}
+
+// isFinite reports whether every element of m is neither NaN nor infinite.
+func isFinite(m mat.Matrix) bool {
+	r, c := m.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			v := m.At(i, j)
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Solver selects how LinearDiscriminant solves the generalized eigenvalue
+// problem of the scatter matrices.
+type Solver int
+
+const (
+	// InverseSolver explicitly inverts the within-class scatter matrix.
+	// It is the default. If the matrix is singular, it falls back to the
+	// pseudo-inverse of SVDSolver, and SetDebug reports why.
+	InverseSolver Solver = iota
+
+	// SVDSolver uses the pseudo-inverse of the within-class scatter matrix
+	// computed from its singular value decomposition, dropping singular
+	// values below the tolerance. It works when the within-class scatter
+	// matrix is singular or nearly so.
+	SVDSolver
+
+	// CholeskySolver solves the symmetric form of the generalized
+	// eigenvalue problem through the Cholesky factorization of the
+	// within-class scatter matrix. It is more stable than inverting the
+	// matrix, and its eigenvalues are real by construction. GetEigen
+	// returns an empty factorization with this solver.
+	CholeskySolver
+)
+
+// SetSolver selects the method used by LinearDiscriminant to solve the
+// generalized eigenvalue problem. The default is InverseSolver.
+func (ld *LD) SetSolver(solver Solver) error {
+	switch solver {
+	case InverseSolver, SVDSolver, CholeskySolver:
+	default:
+		return fmt.Errorf("Invalid solver %d", solver)
+	}
+	ld.solver = solver
+	return nil
+}
+
+// SetShrinkage regularizes the within-class scatter matrix by shrinking it
+// towards a multiple of the identity matrix before it is inverted, which
+// stabilizes the fit when there are few samples relative to the number of
+// features. The scatter matrix Cw is replaced by
+//
+//	(1-lambda)*Cw + lambda*(trace(Cw)/p)*I
+//
+// Parameter lambda is the shrinkage intensity in [0,1]. The default of 0
+// leaves the scatter matrix unchanged.
+func (ld *LD) SetShrinkage(lambda float64) error {
+	if lambda < 0 || lambda > 1 || math.IsNaN(lambda) {
+		return fmt.Errorf("Shrinkage %v is outside [0,1]", lambda)
+	}
+	ld.shrink = lambda
+	return nil
+}
+
+// SetRidge regularizes the within-class covariance matrix by adding alpha
+// to its diagonal before it is inverted, which makes a singular or nearly
+// singular matrix invertible. Unlike SetShrinkage, the rest of the matrix
+// is left unchanged, and alpha is in the units of the variances of the
+// features. It is applied after any shrinkage.
+//
+// Parameter alpha is the ridge, at least 0. The default of 0 leaves the
+// covariance matrix unchanged.
+func (ld *LD) SetRidge(alpha float64) error {
+	if !(alpha >= 0) || math.IsInf(alpha, 1) {
+		return fmt.Errorf("Ridge %v is negative or not finite", alpha)
+	}
+	ld.ridge = alpha
+	return nil
+}
+
+// SetDiagonalCovariance selects whether LinearDiscriminant uses only the
+// per-feature within-class variances, ignoring the covariances between
+// features. This reduces the number of estimated parameters from p² to p,
+// which makes it possible to fit data with more features than samples.
+// It is equivalent to Gaussian naive Bayes with a diagonal covariance matrix
+// shared by all classes. The default is to use the full covariance matrix.
+func (ld *LD) SetDiagonalCovariance(diag bool) {
+	ld.diag = diag
+}
+
+// SetDebug sets a writer that LinearDiscriminant logs diagnostics of each
+// fit to: the class counts, the condition number of the within-class
+// covariance matrix, the eigenvalues before rounding noise is removed, and
+// the number of discriminants retained. A nil writer, the default, turns
+// the diagnostics off.
+func (ld *LD) SetDebug(w io.Writer) {
+	if w == nil {
+		ld.logger = nil
+		return
+	}
+	ld.logger = log.New(w, "lda: ", 0)
+}
+
+// defaultTol is the tolerance used by LinearDiscriminant unless SetTol is called.
+const defaultTol = 1e-4
+
+// pooledCovariance returns the pooled within-class covariance matrix Cw/(n-k).
+func (ld *LD) pooledCovariance() *mat.SymDense {
+	cov := mat.NewSymDense(ld.p, nil)
+	cov.ScaleSym(1/float64(ld.n-ld.k), ld.cw)
+	return cov
+}
+
+// regularize applies the shrinkage, ridge and diagonal covariance settings
+// to the pooled within-class covariance matrix cov, which may be modified.
+func (ld *LD) regularize(cov *mat.SymDense) *mat.SymDense {
+	if ld.shrink != 0 {
+		cov = ld.shrunkCovariance(cov)
+	}
+	if ld.ridge != 0 {
+		for j := 0; j < ld.p; j++ {
+			cov.SetSym(j, j, cov.At(j, j)+ld.ridge)
+		}
+	}
+	if ld.diag {
+		diag := mat.NewSymDense(ld.p, nil)
+		for j := 0; j < ld.p; j++ {
+			diag.SetSym(j, j, cov.At(j, j))
+		}
+		cov = diag
+	}
+	return cov
+}
+
+// pooledCholesky returns the Cholesky factorization of the pooled within-class
+// covariance matrix Cw/(n-k), regularized as in the fit.
+func (ld *LD) pooledCholesky() (*mat.Cholesky, error) {
+	var chol mat.Cholesky
+	if ok := chol.Factorize(ld.regularize(ld.pooledCovariance())); !ok {
+		return nil, fmt.Errorf("Covariance matrix is not positive definite")
+	}
+	return &chol, nil
+}
+
+// ScatterConditionNumber returns the condition number of the within-class
+// scatter matrix, the ratio of its largest to its smallest singular value.
+// A very large condition number means the matrix is close to singular and
+// InverseSolver is unreliable; shrinkage or SVDSolver is safer. It is +Inf
+// if the matrix is singular.
+func (ld *LD) ScatterConditionNumber() (float64, error) {
+	if !ld.ok {
+		return 0, fmt.Errorf("Model has not been fit")
+	}
+	var svd mat.SVD
+	if ok := svd.Factorize(ld.cw, mat.SVDNone); !ok {
+		return 0, fmt.Errorf("SVD factorization failed")
+	}
+	values := svd.Values(nil)
+	min := values[len(values)-1]
+	if min == 0 {
+		return math.Inf(1), nil
+	}
+	return values[0] / min, nil
+}
+
+// shrunkCovariance returns the covariance matrix cov shrunk towards a
+// multiple of the identity matrix: (1-λ)cov + λ(trace(cov)/p)I.
+func (ld *LD) shrunkCovariance(cov *mat.SymDense) *mat.SymDense {
+	mean := mat.Trace(cov) / float64(ld.p)
+	Cw := mat.NewSymDense(ld.p, nil)
+	Cw.ScaleSym(1-ld.shrink, cov)
+	for j := 0; j < ld.p; j++ {
+		Cw.SetSym(j, j, Cw.At(j, j)+ld.shrink*mean)
+	}
+	return Cw
+}
+
+// pseudoInverse computes the Moore-Penrose pseudo-inverse of the pooled
+// within-class covariance matrix Cw from its SVD. Singular values less than
+// tol are treated as zero.
+func (ld *LD) pseudoInverse(Cw mat.Symmetric, tol float64) (*mat.Dense, error) {
+	ld.svd = &mat.SVD{}
+	if !ld.svd.Factorize(Cw, mat.SVDFull) {
+		return nil, fmt.Errorf("SVD factorization failed")
+	}
+	var u, v mat.Dense
+	ld.svd.UTo(&u)
+	ld.svd.VTo(&v)
+	values := ld.svd.Values(nil)
+	for i, value := range values {
+		var scale float64
+		if value >= tol {
+			scale = 1 / value
+		}
+		for j := 0; j < ld.p; j++ {
+			v.Set(j, i, v.At(j, i)*scale)
+		}
+	}
+	inverse := mat.NewDense(ld.p, ld.p, nil)
+	inverse.Mul(&v, u.T())
+	return inverse, nil
+}
+
+// tolerance returns the tolerance set by SetTol, or defaultTol if none was set.
+func (ld *LD) tolerance() float64 {
+	if ld.tol != 0 {
+		return ld.tol
+	}
+	return defaultTol
+}
+
+// SetTol sets the tolerance used to decide if the covariance matrix is
+// singular. LinearDiscriminant rejects variables whose within-class
+// standard deviation is less than tol. The default is 1e-4.
+func (ld *LD) SetTol(tol float64) error {
+	if tol <= 0.0 {
+		return fmt.Errorf("Invalid tol")
+	}
+	ld.tol = tol
+	return nil
+}
+
+// SetPriors sets the priori probability of each class, overriding the class
+// frequencies of the training data. If the model has already been fit, the
+// priors take effect immediately; otherwise they are used by the next call
+// to LinearDiscriminant.
+//
+// Parameter priors holds a positive probability for each class in [0,k).
+// The probabilities must sum to 1.
+func (ld *LD) SetPriors(priors []float64) error {
+	if ld.ct != nil && len(priors) != ld.k {
+		return fmt.Errorf("Got %d priors for %d classes", len(priors), ld.k)
+	}
+	var sum float64
+	for i, prior := range priors {
+		if prior <= 0 {
+			return fmt.Errorf("Prior of class %d is not positive", i)
+		}
+		sum += prior
+	}
+	if math.Abs(sum-1) > 1e-8 {
+		return fmt.Errorf("Priors sum to %v instead of 1", sum)
+	}
+	ld.priors = append([]float64(nil), priors...)
+	if ld.ct != nil {
+		for i, prior := range ld.priors {
+			ld.ct[i] = math.Log(prior)
+		}
+	}
+	return nil
+}
+
+// SetClassWeights sets a weight for each class that scales its contribution
+// to the between-class scatter matrix, on top of its sample count, so the
+// discriminants favor separating the classes with larger weights. The
+// weights are used by the next call to LinearDiscriminant. A nil slice
+// restores the unweighted scatter matrix.
+//
+// Parameter weights holds a positive weight for each class in [0,k).
+func (ld *LD) SetClassWeights(weights []float64) error {
+	if weights == nil {
+		ld.weights = nil
+		return nil
+	}
+	if ld.ct != nil && len(weights) != ld.k {
+		return fmt.Errorf("Got %d class weights for %d classes", len(weights), ld.k)
+	}
+	for i, w := range weights {
+		if !(w > 0) || math.IsInf(w, 0) {
+			return fmt.Errorf("Weight of class %d is not positive", i)
+		}
+	}
+	ld.weights = append([]float64(nil), weights...)
+	return nil
+}
+
+// Priors returns the priori probability of each class used by the fitted
+// model, either the custom priors set by SetPriors or the class frequencies
+// of the training data. It returns nil if the model has not been fit.
+func (ld *LD) Priors() []float64 {
+	if ld.ct == nil {
+		return nil
+	}
+	priors := make([]float64, ld.k)
+	for i, c := range ld.ct {
+		priors[i] = math.Exp(c)
+	}
+	return priors
+}
+
+// UpdatePriors replaces the priori probability of each class of a fitted
+// model, for when the class frequencies drift after deployment. Only the
+// constant terms of the discriminant functions are recomputed: the
+// eigenvectors, eigenvalues and class means are left as they are, so
+// Transform is unaffected while Predict reflects the new priors immediately.
+// Unlike SetPriors, it returns an error if the model has not been fit.
+//
+// Parameter priors holds a positive probability for each class in [0,k).
+// The probabilities must sum to 1.
+func (ld *LD) UpdatePriors(priors []float64) error {
+	if err := ld.checkClassifier(); err != nil {
+		return err
+	}
+	if len(priors) != ld.k {
+		return fmt.Errorf("Got %d priors for %d classes", len(priors), ld.k)
+	}
+	return ld.SetPriors(priors)
+}
+
+// Fit performs linear discriminant analysis. It is the same as
+// LinearDiscriminant and lets LD satisfy the Classifier interface.
+func (ld *LD) Fit(x mat.Matrix, y []int) error {
+	return ld.LinearDiscriminant(x, y)
+}
+
+// FitLabels performs linear discriminant analysis like LinearDiscriminant,
+// but accepts arbitrary string class labels.
+//
+// Parameter x is a matrix of input/training data.
+// Parameter labels is an array of input/training labels, one per row of x.
+// Labels are encoded as class indices in order of first appearance, so the
+// first distinct label becomes class 0, the next class 1, and so on. The
+// mapping is remembered and used by PredictLabel.
+func (ld *LD) FitLabels(x mat.Matrix, labels []string) error {
+	var encoder LabelEncoder
+	y := encoder.Fit(labels)
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		return err
+	}
+	ld.classes = encoder.classes
+	return nil
+}
+
+// FitDropNaN performs linear discriminant analysis like LinearDiscriminant
+// after dropping the rows of x that contain NaN or infinite values.
+//
+// Parameter x is a matrix of input/training data.
+// Parameter y is an array of input/training labels in [0,k). The labels of
+// the remaining rows must still cover [0,k).
+// Returns the number of rows dropped.
+func (ld *LD) FitDropNaN(x mat.Matrix, y []int) (int, error) {
+	r, c := x.Dims()
+	if len(y) != r {
+		return 0, fmt.Errorf("The sizes of X and Y don't match")
+	}
+	var keep []int
+	for i := 0; i < r; i++ {
+		finite := true
+		for j := 0; j < c; j++ {
+			if v := x.At(i, j); math.IsNaN(v) || math.IsInf(v, 0) {
+				finite = false
+				break
+			}
+		}
+		if finite {
+			keep = append(keep, i)
+		}
+	}
+	if len(keep) == 0 {
+		return r, fmt.Errorf("No data to analyze")
+	}
+	clean := mat.NewDense(len(keep), c, nil)
+	cleanY := make([]int, len(keep))
+	for i, row := range keep {
+		for j := 0; j < c; j++ {
+			clean.Set(i, j, x.At(row, j))
+		}
+		cleanY[i] = y[row]
+	}
+	return r - len(keep), ld.LinearDiscriminant(clean, cleanY)
+}
+
+// FitRemap performs linear discriminant analysis like LinearDiscriminant, but
+// accepts any distinct int class labels instead of requiring them to be in
+// [0,k).
+//
+// Parameter x is a matrix of input/training data.
+// Parameter y is an array of input/training labels, one per row of x.
+// The distinct labels are encoded as class indices in increasing order, so
+// the smallest label becomes class 0, the next class 1, and so on. Predict
+// maps its result back to the original label. Methods that take class
+// indices, such as AddSample and TotalCost, still use the encoded classes.
+func (ld *LD) FitRemap(x mat.Matrix, y []int) error {
+	var labels []int
+	var labelMap = map[int]int{}
+	for _, label := range y {
+		if _, ok := labelMap[label]; !ok {
+			labelMap[label] = 0
+			labels = append(labels, label)
+		}
+	}
+	sort.Ints(labels)
+	for c, label := range labels {
+		labelMap[label] = c
+	}
+	encoded := make([]int, len(y))
+	for i, label := range y {
+		encoded[i] = labelMap[label]
+	}
+	if err := ld.LinearDiscriminant(x, encoded); err != nil {
+		return err
+	}
+	ld.labels = labels
 	return nil
 }
 
@@ -191,24 +1119,226 @@ func getRealVectors(e *mat.Eigen) *mat.Dense {
 	return toRealMatrix(&complexVectors)
 }
 
-// Transform performs a transformation on the
-// matrix of the input data, which is represented as an ld.n × p matrix x
+// normalizeSigns orients each column of the eigenvector matrix m so that its
+// element with the largest magnitude is positive. Eigenvectors are only
+// defined up to sign, so this keeps the output of Transform from flipping
+// between runs and platforms.
+func normalizeSigns(m *mat.Dense) {
+	r, c := m.Dims()
+	for j := 0; j < c; j++ {
+		var max float64
+		for i := 0; i < r; i++ {
+			if v := m.At(i, j); math.Abs(v) > math.Abs(max) {
+				max = v
+			}
+		}
+		if max < 0 {
+			for i := 0; i < r; i++ {
+				m.Set(i, j, -m.At(i, j))
+			}
+		}
+	}
+}
+
+// discriminantOrder returns the indices of the eigenvalues ordered by
+// decreasing magnitude, so the most discriminative directions come first.
+func discriminantOrder(evals []complex128) []int {
+	order := make([]int, len(evals))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return cmplx.Abs(evals[order[a]]) > cmplx.Abs(evals[order[b]])
+	})
+	return order
+}
+
+// FitTransform performs linear discriminant analysis on x and y, then
+// transforms x to n dimensions. It is equivalent to calling
+// LinearDiscriminant followed by Transform.
 //
+// Parameter x is a matrix of input/training data.
+// Parameter y is an array of input/training labels in [0,k).
+// Parameter n is the number of dimensions desired.
+// Returns the transformed training data.
+func (ld *LD) FitTransform(x mat.Matrix, y []int, n int) (*mat.Dense, error) {
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		return nil, err
+	}
+	return ld.Transform(x, n)
+}
+
+// FitTransformOnly is like FitTransform, but fits the model for
+// dimensionality reduction only: the constant terms of the discriminant
+// functions and the score statistics aren't computed. Transform and the
+// other projection methods work as usual, while Predict, DecisionFunction
+// and the methods built on them return an error until the model is fit
+// again with LinearDiscriminant.
 //
-// Parameter x is the matrix to be transformed.
+// Parameter x is a matrix of input/training data.
+// Parameter y is an array of input/training labels in [0,k).
 // Parameter n is the number of dimensions desired.
+// Returns the transformed training data.
+func (ld *LD) FitTransformOnly(x mat.Matrix, y []int, n int) (*mat.Dense, error) {
+	if err := ld.fit(context.Background(), x, y, false); err != nil {
+		return nil, err
+	}
+	return ld.Transform(x, n)
+}
+
+// Transform performs a transformation on the
+// matrix of the input data, which is represented as an r × p matrix x
+//
+// The rows of x are centered on the mean of the training data before they
+// are projected, so the projected training data has zero column means.
+//
+// Parameter x is the matrix to be transformed. It must have the same number
+// of columns as the training data.
+// Parameter n is the number of dimensions desired. It must be at least 1 and
+// no more than the number of features or the number of discriminants (k-1).
 // Returns the transformed matrix.
-func (ld *LD) Transform(x mat.Matrix, n int) *mat.Dense {
-	evecs := getRealVectors(&ld.eigen)
-	W := mat.NewDense(ld.p, n, nil)
-	for i := 0; i < n; i++ {
-		temp := mat.Col(nil, i, evecs)
-		W.SetCol(i, temp)
+func (ld *LD) Transform(x mat.Matrix, n int) (*mat.Dense, error) {
+	if err := ld.checkDimensions(n); err != nil {
+		return nil, err
+	}
+	r, _ := x.Dims()
+	result := mat.NewDense(r, n, nil)
+	if err := ld.TransformTo(result, x, n); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TransformTo performs the same transformation as Transform, but stores the
+// result in dst instead of allocating a new matrix, so a buffer can be
+// reused across many calls.
+//
+// Parameter dst receives the transformed matrix. It must be r × n.
+// Parameter x is the r × p matrix to be transformed.
+// Parameter n is the number of dimensions desired, as for Transform.
+func (ld *LD) TransformTo(dst *mat.Dense, x mat.Matrix, n int) error {
+	W, err := ld.projection(n)
+	if err != nil {
+		return err
+	}
+	r, c := x.Dims()
+	if c != ld.p {
+		return fmt.Errorf("Input has %d features, model trained on %d", c, ld.p)
+	}
+	if dr, dc := dst.Dims(); dr != r || dc != n {
+		return fmt.Errorf("Destination is %d×%d, want %d×%d", dr, dc, r, n)
+	}
+	dst.Mul(ld.standardizeMatrix(x), W)
+
+	// Subtracting the projected mean from every row centers the result
+	// without copying x
+	for c := 0; c < n; c++ {
+		var m float64
+		for j := 0; j < ld.p; j++ {
+			m += ld.mean[j] * W.At(j, c)
+		}
+		for i := 0; i < r; i++ {
+			dst.Set(i, c, dst.At(i, c)-m)
+		}
+	}
+	return nil
+}
+
+// TransformWhitened transforms x like Transform, then divides each projected
+// column by the within-class standard deviation along its eigenvector, so the
+// pooled within-class covariance of the output is the identity matrix.
+// Directions whose within-class variance is below the squared tolerance are
+// rejected rather than scaled up.
+//
+// Parameter x is the matrix to be transformed.
+// Parameter n is the number of dimensions desired, as for Transform.
+func (ld *LD) TransformWhitened(x mat.Matrix, n int) (*mat.Dense, error) {
+	result, err := ld.Transform(x, n)
+	if err != nil {
+		return nil, err
+	}
+	tol := ld.tolerance()
+	order := discriminantOrder(ld.evals)
+	r, _ := result.Dims()
+	for c := 0; c < n; c++ {
+		v := ld.wvar[order[c]]
+		if v < tol*tol {
+			return nil, fmt.Errorf("Within-class variance of discriminant %d is close to zero", c)
+		}
+		sd := math.Sqrt(v)
+		for i := 0; i < r; i++ {
+			result.Set(i, c, result.At(i, c)/sd)
+		}
+	}
+	return result, nil
+}
+
+// ProjectionMatrix returns the p×n matrix whose columns are the n most
+// discriminative eigenvectors, the loadings that map each feature to each
+// discriminant axis. Transform multiplies the data by this matrix, after
+// standardizing it if SetStandardize is enabled and centering it on Mean.
+//
+// Parameter n is the number of dimensions, with the same limits as for
+// Transform.
+func (ld *LD) ProjectionMatrix(n int) (*mat.Dense, error) {
+	W, err := ld.projection(n)
+	if err != nil {
+		return nil, err
+	}
+	return mat.DenseCopyOf(W), nil
+}
+
+// checkDimensions returns an error if the model has not been fit or n is not
+// a valid number of dimensions to transform data to.
+func (ld *LD) checkDimensions(n int) error {
+	if !ld.ok {
+		return fmt.Errorf("Model has not been fit")
+	}
+	if n < 1 {
+		return fmt.Errorf("Number of dimensions %d is less than 1", n)
+	}
+	if n > ld.p {
+		return fmt.Errorf("Number of dimensions %d exceeds the number of features %d", n, ld.p)
+	}
+	if n > ld.k-1 {
+		return fmt.Errorf("Number of dimensions %d exceeds the number of discriminants %d", n, ld.k-1)
 	}
-	result := mat.NewDense(ld.n, n, nil)
-	result.Mul(x, W)
+	return nil
+}
+
+// projection returns the p×n matrix whose columns are the n eigenvectors
+// with the largest eigenvalues, which Transform uses to project the data.
+// The matrix is a view of the cached eigenvectors and must not be modified.
+func (ld *LD) projection(n int) (*mat.Dense, error) {
+	if err := ld.checkDimensions(n); err != nil {
+		return nil, err
+	}
+	return ld.svecs.Slice(0, ld.p, 0, n).(*mat.Dense), nil
+}
 
-	return result
+// InverseTransform maps data projected by Transform back to an approximation
+// in the original feature space by multiplying it by the transpose of the
+// projection matrix and adding back the mean of the training data.
+//
+// Parameter projected is an r × n matrix returned by Transform. Its number of
+// columns determines the projection matrix, so it must be a valid number of
+// dimensions for Transform.
+// Returns the r × p reconstruction.
+func (ld *LD) InverseTransform(projected *mat.Dense) (*mat.Dense, error) {
+	r, n := projected.Dims()
+	W, err := ld.projection(n)
+	if err != nil {
+		return nil, err
+	}
+	result := mat.NewDense(r, ld.p, nil)
+	result.Mul(projected, W.T())
+	for i := 0; i < r; i++ {
+		for j := 0; j < ld.p; j++ {
+			result.Set(i, j, result.At(i, j)+ld.mean[j])
+		}
+	}
+	ld.unstandardizeMatrix(result)
+	return result, nil
 }
 
 // Predict performs a prediction based on training data
@@ -223,47 +1353,416 @@ func (ld *LD) Transform(x mat.Matrix, n int) *mat.Dense {
 // LDA reduces dimensionality of the data and performs feature extraction
 // to maximize separation between classes.
 // Precondition: training data must be labeled and labels must be ints starting
-// from 0.
+// from 0, unless the model was fit with FitRemap, in which case the original
+// label is returned.
+// If several classes have exactly the same largest score, the one with the
+// lowest class index is returned. PredictTopK, Classify and the other
+// prediction methods break ties the same way.
+// Predict only reads the fitted model, so it is safe to call from multiple
+// goroutines as long as the model is not refit or updated concurrently.
 func (ld *LD) Predict(x []float64) (int, error) {
+	r, err := ld.Classify(x)
+	return r.Class, err
+}
+
+// predictClass returns the index of the class with the largest discriminant
+// score for x.
+func (ld *LD) predictClass(x []float64) (int, error) {
+	scores, err := ld.DecisionFunction(x)
+	if err != nil {
+		return 0, err
+	}
+	return argmax(scores), nil
+}
+
+// checkClassifier returns an error if the model can't classify, because it
+// has not been fit or was fit by FitTransformOnly.
+func (ld *LD) checkClassifier() error {
+	if !ld.ok {
+		return fmt.Errorf("Model has not been fit")
+	}
+	if ld.transformOnly {
+		return fmt.Errorf("Model fitted for transform only")
+	}
+	return nil
+}
+
+// DecisionFunction computes the discriminant score of each class for the
+// input x, ct[i] - 0.5*f where f is the squared Mahalanobis distance of x
+// from the mean of class i, as returned by MahalanobisDistances. The class
+// with the largest score is the prediction.
+//
+// Parameter x is the set of data to score.
+// Returns a slice of length k with the score of each class in class order.
+func (ld *LD) DecisionFunction(x []float64) ([]float64, error) {
+	if err := ld.checkClassifier(); err != nil {
+		return nil, err
+	}
+	scores, err := ld.MahalanobisDistances(x)
+	if err != nil {
+		return nil, err
+	}
+	for i, f := range scores {
+		scores[i] = float64(ld.ct[i]) - (0.5 * f)
+	}
+	return scores, nil
+}
 
+// MahalanobisDistances computes the squared Mahalanobis distance of the
+// input x from the mean of each class under the pooled within-class
+// covariance. The distance is measured in the eigenspace, along the (at most
+// k-1) eigenvectors with nonzero eigenvalues, each scaled by its within-class
+// standard deviation. The part of x outside those directions is equally far
+// from every class mean and is left out, so the distances differ from the
+// full Mahalanobis distances by a term shared by all classes.
+//
+// Parameter x is the vector to measure, of length p.
+// Returns a slice of length k with the distance to each class in class order.
+func (ld *LD) MahalanobisDistances(x []float64) ([]float64, error) {
+	if !ld.ok {
+		return nil, fmt.Errorf("Model has not been fit")
+	}
 	if len(x) != ld.p {
-		return 0, fmt.Errorf("Invalid input vector size")
+		return nil, fmt.Errorf("Invalid input vector size")
 	}
-	var y = 0
-	var max = math.Inf(-1)
+	x = ld.standardizeRow(x)
+	dists := make([]float64, ld.k)
 	d := make([]float64, ld.p)
 	ux := make([]float64, ld.p)
 	UX := mat.NewDense(len(ux), 1, ux)
+	D := mat.NewDense(len(d), 1, d)
+	Atr := ld.evecs.T()
 
 	for i := 0; i < ld.k; i++ {
 		for j := 0; j < ld.p; j++ {
 			d[j] = x[j] - ld.mu.At(i, j)
 		}
-		evecs := getRealVectors(&ld.eigen)
-		Atr := evecs.T()
-		D := mat.NewDense(len(d), 1, d)
 		UX.Mul(Atr, D) // eigen vector transpose * (measurement - sum of class means)
 		var f float64
-		evals := make([]complex128, ld.p)
-		ld.eigen.Values(evals)
-		for j := 0; j < ld.p; j++ {
-			f += UX.At(j, 0) * UX.At(j, 0) / cmplx.Abs(evals[j]) // (weighted sum of the result squared) / eigen value
+		for _, j := range ld.dirs {
+			f += UX.At(j, 0) * UX.At(j, 0) / ld.wvar[j] // (weighted sum of the result squared) / within-class variance
 		}
-		f = float64(ld.ct[i]) - (0.5 * f)
-		if max < f {
-			max = f
+		dists[i] = f
+	}
+	return dists, nil
+}
+
+// PredictProba computes the posterior probability of each class for the
+// input x by applying a softmax to the discriminant scores used by Predict.
+//
+// Parameter x is the set of data to classify.
+// Returns a slice of length k whose entries sum to 1. Its largest entry
+// corresponds to the class returned by Predict.
+func (ld *LD) PredictProba(x []float64) ([]float64, error) {
+	scores, err := ld.DecisionFunction(x)
+	if err != nil {
+		return nil, err
+	}
+	softmax(scores)
+	return scores, nil
+}
+
+// PredictWithMargin performs a prediction like Predict and also returns the
+// margin between the best and second best discriminant scores. A small
+// margin means x is close to the boundary between two classes, so callers
+// can decline to classify it.
+//
+// Parameter x is the set of data to classify.
+func (ld *LD) PredictWithMargin(x []float64) (class int, margin float64, err error) {
+	r, err := ld.Classify(x)
+	return r.Class, r.Margin, err
+}
+
+// PredictWithProbabilities performs a prediction like Predict and also
+// returns the posterior probability of each class, as computed by
+// PredictProba, for callers that threshold or rank on the probabilities.
+//
+// Parameter x is the set of data to classify.
+func (ld *LD) PredictWithProbabilities(x []float64) (class int, prob []float64, err error) {
+	r, err := ld.Classify(x)
+	return r.Class, r.Prob, err
+}
+
+// PredictTopK returns the topk classes with the largest discriminant scores
+// for x, most likely first. Classes with equal scores are ordered by class
+// index, lowest first.
+//
+// Parameter x is the set of data to classify.
+// Parameter topk is the number of classes to return, in [1,k].
+func (ld *LD) PredictTopK(x []float64, topk int) ([]int, error) {
+	if err := ld.checkClassifier(); err != nil {
+		return nil, err
+	}
+	if topk < 1 || topk > ld.k {
+		return nil, fmt.Errorf("Invalid number of classes %d", topk)
+	}
+	scores, err := ld.DecisionFunction(x)
+	if err != nil {
+		return nil, err
+	}
+	order := make([]int, ld.k)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+	return order[:topk], nil
+}
+
+// PredictNearestMean returns the class whose mean is closest to x in
+// Euclidean distance after both are projected onto the n most discriminative
+// eigenvectors, as by Transform. It is a simpler alternative to Predict that
+// ignores the priors and the within-class variance along each direction.
+//
+// Parameter x is the set of data to classify.
+// Parameter n is the number of dimensions to compare in, with the same
+// limits as for Transform.
+func (ld *LD) PredictNearestMean(x []float64, n int) (int, error) {
+	if !ld.ok {
+		return 0, fmt.Errorf("Model has not been fit")
+	}
+	if len(x) != ld.p {
+		return 0, fmt.Errorf("Invalid input vector size")
+	}
+	W, err := ld.projection(n)
+	if err != nil {
+		return 0, err
+	}
+	var z, m mat.VecDense
+	z.MulVec(W.T(), mat.NewVecDense(ld.p, ld.standardizeRow(x)))
+	y := 0
+	min := math.Inf(1)
+	for i := 0; i < ld.k; i++ {
+		m.MulVec(W.T(), ld.mu.RowView(i))
+		m.SubVec(&m, &z)
+		if d := mat.Dot(&m, &m); d < min {
+			min = d
 			y = i
 		}
 	}
+	if ld.labels != nil {
+		y = ld.labels[y]
+	}
 	return y, nil
 }
 
-// GetEigen is a getter method for eigen values
+// TransformAndPredict projects x onto the n most discriminative
+// eigenvectors, as Transform does for a matrix, and also predicts its class
+// as Predict does.
 //
+// Parameter x is the set of data to project and classify, of length p.
+// Parameter n is the number of dimensions desired, as for Transform.
+func (ld *LD) TransformAndPredict(x []float64, n int) (coords []float64, class int, err error) {
+	if !ld.ok {
+		return nil, 0, fmt.Errorf("Model has not been fit")
+	}
+	if len(x) != ld.p {
+		return nil, 0, fmt.Errorf("Invalid input vector size")
+	}
+	W, err := ld.projection(n)
+	if err != nil {
+		return nil, 0, err
+	}
+	z := ld.standardizeRow(x)
+	coords = make([]float64, n)
+	for c := range coords {
+		for j := 0; j < ld.p; j++ {
+			coords[c] += (z[j] - ld.mean[j]) * W.At(j, c)
+		}
+	}
+	class, err = ld.Predict(x)
+	if err != nil {
+		return nil, 0, err
+	}
+	return coords, class, nil
+}
+
+// PredictLabel performs a prediction like Predict and returns the original
+// string label of the predicted class.
+// The model must have been trained with FitLabels.
+func (ld *LD) PredictLabel(x []float64) (string, error) {
+	if ld.classes == nil {
+		return "", fmt.Errorf("Model was not fit with string labels")
+	}
+	c, err := ld.predictClass(x)
+	if err != nil {
+		return "", err
+	}
+	return ld.classes[c], nil
+}
+
+// BenchmarkPredict measures the average latency of a call to Predict.
+//
+// Parameter samples is the number of random input vectors to classify.
+// The vectors are drawn around the class means with a fixed seed and are
+// generated before timing starts.
+// Returns the average duration of a single call to Predict.
+func (ld *LD) BenchmarkPredict(samples int) (perCall time.Duration, err error) {
+	if samples < 1 {
+		return 0, fmt.Errorf("Invalid number of samples")
+	}
+	if !ld.ok {
+		return 0, fmt.Errorf("Model has not been fit")
+	}
+	rnd := rand.New(rand.NewSource(1))
+	inputs := make([][]float64, samples)
+	for i := range inputs {
+		inputs[i] = make([]float64, ld.p)
+		for j := 0; j < ld.p; j++ {
+			inputs[i][j] = ld.mu.At(i%ld.k, j) + rnd.NormFloat64()
+		}
+	}
+
+	start := time.Now()
+	for _, x := range inputs {
+		if _, err := ld.Predict(x); err != nil {
+			return 0, err
+		}
+	}
+	return time.Since(start) / time.Duration(samples), nil
+}
+
+// GetEigen is a getter method for eigen values
 //
+// The factorization is empty for models with a single feature or fit with
+// CholeskySolver; EigenValues and SortedEigensystem work for every model.
 //
 // No parameters.
 // Returns a mat.Eigen object
 func (ld *LD) GetEigen() mat.Eigen {
 	return ld.eigen
 }
+
+// EigenValues returns the real parts of the eigenvalues in descending order
+// of magnitude, the order of the components returned by Transform, or nil
+// if the model has not been fit.
+func (ld *LD) EigenValues() []float64 {
+	if !ld.ok {
+		return nil
+	}
+	order := discriminantOrder(ld.evals)
+	values := make([]float64, len(order))
+	for i, j := range order {
+		values[i] = real(ld.evals[j])
+	}
+	return values
+}
+
+// MaxImaginaryComponent returns the largest absolute imaginary part of the
+// eigenvalues and of the discriminant eigenvectors, which are discarded
+// after fitting. The eigenvalues are real in exact arithmetic, so anything
+// more than rounding error means the eigensystem, and so Transform and
+// Predict, can't be trusted. It returns 0 with CholeskySolver, which only
+// produces real results, and for a model that has not been fit.
+func (ld *LD) MaxImaginaryComponent() float64 {
+	return ld.imag
+}
+
+// SortedEigensystem returns the real parts of the eigenvalues in descending
+// order along with the matching eigenvectors, one per column, in the order
+// used by Transform. Each eigenvector is oriented with its element of largest
+// magnitude positive.
+func (ld *LD) SortedEigensystem() (values []float64, vectors *mat.Dense, err error) {
+	if !ld.ok {
+		return nil, nil, fmt.Errorf("Model has not been fit")
+	}
+	return ld.EigenValues(), mat.DenseCopyOf(ld.svecs), nil
+}
+
+// String summarizes the fitted model: the number of observations, features
+// and classes, and the eigenvalue and explained variance ratio of each
+// discriminant. It returns "LD: unfitted" if the model has not been fit.
+func (ld *LD) String() string {
+	if !ld.ok {
+		return "LD: unfitted"
+	}
+	values := ld.EigenValues()[:len(ld.dirs)]
+	var b strings.Builder
+	fmt.Fprintf(&b, "LD: %d observations, %d features, %d classes", ld.n, ld.p, ld.k)
+	b.WriteString("\n  eigenvalues:")
+	for _, v := range values {
+		fmt.Fprintf(&b, " %.4g", v)
+	}
+	b.WriteString("\n  explained variance:")
+	for _, r := range ld.ExplainedVarianceRatio() {
+		fmt.Fprintf(&b, " %.4f", r)
+	}
+	return b.String()
+}
+
+// IsFitted reports whether the model has been fit successfully. Methods
+// that need a fitted model return an error instead of panicking when it is
+// false, which includes after a failed fit or Reset.
+func (ld *LD) IsFitted() bool {
+	return ld.ok
+}
+
+// ClassMeans returns a copy of the k×p matrix of class mean vectors, or nil
+// if the model has not been fit.
+func (ld *LD) ClassMeans() *mat.Dense {
+	if !ld.ok {
+		return nil
+	}
+	means := mat.DenseCopyOf(ld.mu)
+	ld.unstandardizeMatrix(means)
+	return means
+}
+
+// ProjectedClassMeans returns the k×n matrix of class centroids in the
+// space of the first n discriminants, the class means transformed like
+// Transform transforms the data, so they can be drawn over a plot of the
+// projection.
+//
+// Parameter n is the number of dimensions desired, with the same limits as
+// for Transform.
+func (ld *LD) ProjectedClassMeans(n int) (*mat.Dense, error) {
+	if !ld.ok {
+		return nil, fmt.Errorf("Model has not been fit")
+	}
+	W, err := ld.projection(n)
+	if err != nil {
+		return nil, err
+	}
+	centered := mat.DenseCopyOf(ld.mu)
+	for i := 0; i < ld.k; i++ {
+		for j := 0; j < ld.p; j++ {
+			centered.Set(i, j, centered.At(i, j)-ld.mean[j])
+		}
+	}
+	centroids := mat.NewDense(ld.k, n, nil)
+	centroids.Mul(centered, W)
+	return centroids, nil
+}
+
+// NumClasses returns the number of classes k of the fitted model.
+func (ld *LD) NumClasses() int {
+	return ld.k
+}
+
+// Mean returns a copy of the mean vector of the training data, which
+// Transform subtracts before projecting, or nil if the model has not been
+// fit. It is in the original units even if the model standardizes.
+func (ld *LD) Mean() []float64 {
+	if ld.mean == nil {
+		return nil
+	}
+	mean := make([]float64, ld.p)
+	for j, v := range ld.mean {
+		mean[j] = v
+		if ld.scale != nil {
+			mean[j] = v*ld.scale[j] + ld.center[j]
+		}
+	}
+	return mean
+}
+
+// ClassCounts returns a copy of the number of training samples in each
+// class, or nil if the model has not been fit. A large imbalance between the
+// classes may call for custom priors, see SetPriors.
+func (ld *LD) ClassCounts() []int {
+	if ld.ni == nil {
+		return nil
+	}
+	return append([]int(nil), ld.ni...)
+}
@@ -2,35 +2,40 @@ package lda
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"encoding/csv"
 	"fmt"
-	"image/color"
 	"io"
 	"log"
+	"math"
+	"math/cmplx"
+	"math/rand"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"gonum.org/v1/gonum/mat"
-	"gonum.org/v1/plot"
-	"gonum.org/v1/plot/plotter"
-	"gonum.org/v1/plot/vg"
-	"gonum.org/v1/plot/vg/draw"
 )
 
-func TestLinearDiscriminant(t *testing.T) {
-	// Threshold for detecting zero variances
-	var ld LD
-	const epsilon = 1e-15
-
-	// Iris dataset training file
-	trainFile, _ := os.Open("iris/iris.data")
+// loadIris reads the Iris dataset and returns its feature matrix along with
+// the species labels mapped to ints in order of first appearance, and the
+// raw species labels.
+func loadIris(tb testing.TB) (*mat.Dense, []int, []string) {
+	trainFile, err := os.Open("iris/iris.data")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer trainFile.Close()
 	rTrain := csv.NewReader(bufio.NewReader(trainFile))
 	rTrain.Comma = ','
 	var trainingDataText []string
 	var trainingDataNumbers []float64
 	var labels []string
-	var labelsNumbers []int
 	var numRows int
 	for {
 		trainRecord, err := rTrain.Read()
@@ -54,26 +59,31 @@ func TestLinearDiscriminant(t *testing.T) {
 	dataMatrix := mat.NewDense(numRows, 4, trainingDataNumbers)
 
 	// Map of labels to ints
-	var m = map[string]int{}
-	i := 0
-	for _, value := range labels {
-		if _, ok := m[value]; !ok {
-			m[value] = i
-			i++
-		}
-	}
+	var encoder LabelEncoder
+	labelsNumbers := encoder.Fit(labels)
+	return dataMatrix, labelsNumbers, labels
+}
 
-	for value := range labels {
-		labelsNumbers = append(labelsNumbers, m[labels[value]])
-	}
+func TestLinearDiscriminant(t *testing.T) {
+	// Threshold for detecting zero variances
+	var ld LD
+	const epsilon = 1e-15
+
+	// Iris dataset training file
+	dataMatrix, labelsNumbers, _ := loadIris(t)
 
 	ok := ld.LinearDiscriminant(dataMatrix, labelsNumbers) // Calling LinearDiscriminant on Iris data
 	if ok == nil {
 		fmt.Println("Call to LDA successful")
 		numDims := 2
-		result := ld.Transform(dataMatrix, numDims)
+		result, err := ld.Transform(dataMatrix, numDims)
+		if err != nil {
+			t.Fatal(err)
+		}
 		// Graphing results of the transformation
-		PlotLDA(result, labelsNumbers, "Iris-data-LDA-graph.png", "LDA: Iris Dataset")
+		if err := PlotLDA(result, labelsNumbers, filepath.Join(t.TempDir(), "Iris-data-LDA-graph.png"), "LDA: Iris Dataset"); err != nil {
+			t.Fatal(err)
+		}
 	}
 
 tests:
@@ -100,7 +110,11 @@ tests:
 				-0.5465, 0.2543, -0.2664, 0.4156,
 				-0.7138, -0.767, -0.247, -0.8402,
 			}),
-			wantVars:  []float64{32.27195779972984 + 0i, 0.2775668638400518 + 0i, -5.788566151963261e-16 + 0i, -1.7908428048920807e-14 + 0i},
+			// The eigenvalues are those of the pooled covariance, Cw/(n-k), so
+			// they are (n-k) = 147 times the values for the raw scatter matrix
+			// (32.272 and 0.27757). The trailing eigenvalues are rounding
+			// error and are set to zero.
+			wantVars:  []float64{4743.977796560289, 40.80232898448728, 0, 0},
 			wantClass: []int{2, 0, 1},
 			epsilon:   1e-12,
 		},
@@ -114,7 +128,11 @@ tests:
 				continue tests
 			}
 			numDims := 2
-			result := ld.Transform(test.data, numDims)
+			result, err := ld.Transform(test.data, numDims)
+			if err != nil {
+				t.Errorf("unexpected Transform failure for test %d: %v", i, err)
+				continue tests
+			}
 			r, _ := test.testPredict.Dims()
 			for k := 0; k < r; k++ {
 				c, _ := ld.Predict(test.testPredict.RawRowView(k))
@@ -122,6 +140,12 @@ tests:
 					t.Errorf("unexpected prediction result %v got:%v, want:%v", k, c, test.wantClass[k])
 				}
 			}
+			for k, want := range test.wantVars {
+				got := cmplx.Abs(ld.evals[k])
+				if math.Abs(got-want) > test.epsilon*math.Max(1, want) {
+					t.Errorf("unexpected eigenvalue %d got:%v, want:%v", k, got, want)
+				}
+			}
 			values := make([]string, ld.p)
 			for j := 0; j < ld.n; j++ {
 				row := result.RawRowView(j)
@@ -133,60 +157,1995 @@ tests:
 	}
 }
 
-func checkError(message string, err error) {
+func TestEigenValues(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if ld.EigenValues() != nil {
+		t.Error("expected nil eigenvalues for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	values := ld.EigenValues()
+	if len(values) != 4 {
+		t.Fatalf("unexpected number of eigenvalues got:%d, want:4", len(values))
+	}
+	// 32.272 for the raw scatter matrices, scaled by (n-k) = 147 for the
+	// pooled covariance
+	if want := 32.27195779972984 * 147; math.Abs(values[0]-want) > 1e-6 {
+		t.Errorf("unexpected first eigenvalue got:%v, want:%v", values[0], want)
+	}
+	for i := 1; i < len(values); i++ {
+		if math.Abs(values[i]) > math.Abs(values[i-1]) {
+			t.Errorf("eigenvalues are not in decreasing order of magnitude: %v", values)
+			break
+		}
+	}
+}
+
+func TestSortedEigensystem(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if _, _, err := ld.SortedEigensystem(); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	values, vectors, err := ld.SortedEigensystem()
 	if err != nil {
-		log.Fatal(message, err)
+		t.Fatal(err)
+	}
+	for i := 1; i < len(values); i++ {
+		if values[i] > values[i-1] {
+			t.Errorf("eigenvalues are not sorted: %v", values)
+			break
+		}
+	}
+	if r, c := vectors.Dims(); r != 4 || c != 4 {
+		t.Fatalf("unexpected dimensions got:%d×%d, want:4×4", r, c)
+	}
+	// First column of wantVecs in TestLinearDiscriminant, negated so that
+	// its largest element is positive
+	const epsilon = 1e-4
+	want := []float64{-0.2049, -0.3871, 0.5465, 0.7138}
+	for i, w := range want {
+		if got := vectors.At(i, 0); math.Abs(got-w) > epsilon {
+			t.Errorf("unexpected eigenvector element %d got:%v, want:%v", i, got, w)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	var ld LD
+	if got := ld.String(); got != "LD: unfitted" {
+		t.Errorf("unexpected summary of an unfitted model got:%q", got)
+	}
+	dataMatrix, labels, _ := loadIris(t)
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	got := ld.String()
+	for _, want := range []string{"150 observations", "4 features", "3 classes", "4744", "0.9915"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary %q doesn't mention %q", got, want)
+		}
+	}
+}
+
+func TestNormalizeSigns(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	want := mat.DenseCopyOf(ld.evecs)
+	r, c := want.Dims()
+	for j := 0; j < c; j++ {
+		var max float64
+		for i := 0; i < r; i++ {
+			if v := want.At(i, j); math.Abs(v) > math.Abs(max) {
+				max = v
+			}
+		}
+		if max <= 0 {
+			t.Errorf("largest element of eigenvector %d is not positive: %v", j, max)
+		}
+	}
+
+	// Flip every other eigenvector and normalize again
+	flipped := mat.DenseCopyOf(want)
+	for j := 0; j < c; j += 2 {
+		for i := 0; i < r; i++ {
+			flipped.Set(i, j, -flipped.At(i, j))
+		}
+	}
+	normalizeSigns(flipped)
+	if !mat.Equal(flipped, want) {
+		t.Errorf("unexpected eigenvectors after normalizing got:%v, want:%v", mat.Formatted(flipped), mat.Formatted(want))
+	}
+}
+
+func TestClassMeans(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if ld.ClassMeans() != nil {
+		t.Error("expected nil class means for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if ld.NumClasses() != 3 {
+		t.Errorf("unexpected number of classes got:%d, want:3", ld.NumClasses())
+	}
+	means := ld.ClassMeans()
+	if r, c := means.Dims(); r != 3 || c != 4 {
+		t.Fatalf("unexpected dimensions got:%d×%d, want:3×4", r, c)
+	}
+	// Setosa is class 2
+	want := []float64{5.006, 3.418, 1.464, 0.244}
+	for j, v := range want {
+		if math.Abs(means.At(2, j)-v) > 1e-9 {
+			t.Errorf("unexpected Setosa mean %d got:%v, want:%v", j, means.At(2, j), v)
+		}
+	}
+	means.Set(0, 0, 100)
+	if ld.mu.At(0, 0) == 100 {
+		t.Error("modifying the returned means changed the model")
+	}
+}
+
+func TestClassCounts(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if ld.ClassCounts() != nil {
+		t.Error("expected nil class counts for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	counts := ld.ClassCounts()
+	if want := []int{50, 50, 50}; len(counts) != len(want) || counts[0] != 50 || counts[1] != 50 || counts[2] != 50 {
+		t.Errorf("unexpected class counts got:%v, want:%v", counts, want)
+	}
+	counts[0] = 0
+	if ld.ni[0] != 50 {
+		t.Error("modifying the returned counts changed the model")
+	}
+}
+
+func TestMean(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if ld.Mean() != nil {
+		t.Error("expected a nil mean for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{5.843333, 3.054, 3.758667, 1.198667}
+	mean := ld.Mean()
+	for j, v := range want {
+		if math.Abs(mean[j]-v) > 1e-6 {
+			t.Errorf("unexpected mean of column %d got:%v, want:%v", j, mean[j], v)
+		}
+	}
+
+	// The projected training data is centered
+	result, err := ld.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, c := result.Dims()
+	for j := 0; j < c; j++ {
+		var sum float64
+		for i := 0; i < r; i++ {
+			sum += result.At(i, j)
+		}
+		if math.Abs(sum/float64(r)) > 1e-9 {
+			t.Errorf("unexpected mean of projected column %d got:%v, want:0", j, sum/float64(r))
+		}
+	}
+
+	// Centering doesn't change the predictions, which depend only on
+	// differences from the class means
+	var correct int
+	for i := 0; i < r; i++ {
+		if c, _ := ld.Predict(dataMatrix.RawRowView(i)); c == labels[i] {
+			correct++
+		}
+	}
+	if correct != 147 {
+		t.Errorf("unexpected number of correct predictions got:%d, want:147", correct)
+	}
+}
+
+func TestFitTransform(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if _, err := ld.FitTransform(dataMatrix, labels[1:], 2); err == nil {
+		t.Error("expected an error for mismatched labels")
+	}
+	if _, err := ld.FitTransform(dataMatrix, labels, 3); err == nil {
+		t.Error("expected an error for more dimensions than discriminants")
+	}
+
+	got, err := ld.FitTransform(dataMatrix, labels, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, c := got.Dims(); r != 150 || c != 2 {
+		t.Fatalf("unexpected dimensions got:%d×%d, want:150×2", r, c)
+	}
+	var separate LD
+	if err := separate.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	want, err := separate.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mat.Equal(got, want) {
+		t.Error("FitTransform differs from LinearDiscriminant followed by Transform")
+	}
+}
+
+func TestProjectionMatrix(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{0, 3} {
+		if _, err := ld.ProjectionMatrix(n); err == nil {
+			t.Errorf("expected an error for %d dimensions", n)
+		}
+	}
+	W, err := ld.ProjectionMatrix(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, c := W.Dims(); r != 4 || c != 2 {
+		t.Fatalf("unexpected dimensions got:%d×%d, want:4×2", r, c)
+	}
+	centered := mat.DenseCopyOf(dataMatrix)
+	mean := ld.Mean()
+	r, c := centered.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			centered.Set(i, j, centered.At(i, j)-mean[j])
+		}
+	}
+	var got mat.Dense
+	got.Mul(centered, W)
+	want, err := ld.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mat.EqualApprox(&got, want, 1e-12) {
+		t.Error("projecting with ProjectionMatrix differs from Transform")
+	}
+}
+
+func TestTransformTo(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.TransformTo(mat.NewDense(150, 1, nil), dataMatrix, 2); err == nil {
+		t.Error("expected an error for a destination of the wrong size")
+	}
+	want, err := ld.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := mat.NewDense(150, 2, nil)
+	if err := ld.TransformTo(dst, dataMatrix, 2); err != nil {
+		t.Fatal(err)
+	}
+	if !mat.Equal(dst, want) {
+		t.Error("TransformTo differs from Transform")
+	}
+}
+
+func TestTransformFeatureMismatch(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ld.Transform(mat.NewDense(3, 5, nil), 2)
+	if err == nil {
+		t.Fatal("expected an error for a 5-column matrix")
+	}
+	if want := "Input has 5 features, model trained on 4"; err.Error() != want {
+		t.Errorf("unexpected error got:%q, want:%q", err, want)
+	}
+}
+
+// benchmarkTransformBatches projects 1000 batches of 10 Iris rows.
+func benchmarkTransformBatches(b *testing.B, reuse bool) {
+	dataMatrix, labels, _ := loadIris(b)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		b.Fatal(err)
+	}
+	dst := mat.NewDense(10, 2, nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for batch := 0; batch < 1000; batch++ {
+			start := batch % 14 * 10
+			x := dataMatrix.Slice(start, start+10, 0, 4)
+			var err error
+			if reuse {
+				err = ld.TransformTo(dst, x, 2)
+			} else {
+				_, err = ld.Transform(x, 2)
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkTransform(b *testing.B)   { benchmarkTransformBatches(b, false) }
+func BenchmarkTransformTo(b *testing.B) { benchmarkTransformBatches(b, true) }
+
+func TestTransformHoldout(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	holdout := mat.NewDense(3, 4, []float64{
+		5.0, 3.3, 1.4, 0.2,
+		5.1, 2.5, 3.0, 1.1,
+		7.7, 3.0, 6.1, 2.3,
+	})
+	result, err := ld.Transform(holdout, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, c := result.Dims(); r != 3 || c != 2 {
+		t.Errorf("unexpected dimensions got:%d×%d, want:3×2", r, c)
+	}
+}
+
+func TestInverseTransform(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	projected, err := ld.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recon, err := ld.InverseTransform(projected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _ := dataMatrix.Dims()
+	if rr, c := recon.Dims(); rr != r || c != 4 {
+		t.Fatalf("unexpected dimensions got:%d×%d, want:%d×4", rr, c, r)
+	}
+	if max := mat.Max(dataMatrix); math.Abs(mat.Max(recon)) > 10*max || math.Abs(mat.Min(recon)) > 10*max {
+		t.Errorf("reconstruction is unbounded: [%v, %v]", mat.Min(recon), mat.Max(recon))
+	}
+
+	if _, err := ld.InverseTransform(mat.NewDense(2, 3, nil)); err == nil {
+		t.Error("expected an error for an invalid number of columns")
+	}
+}
+
+func TestTransformInvalidDims(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{0, ld.k, ld.p + 1} {
+		if _, err := ld.Transform(dataMatrix, n); err == nil {
+			t.Errorf("expected an error transforming to %d dimensions", n)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.SetTol(1e-6); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	ld.Reset()
+	if _, err := ld.Predict([]float64{5.0, 3.3, 1.4, 0.2}); err == nil {
+		t.Error("expected an error predicting with a reset model")
+	}
+	if ld.tol != 1e-6 {
+		t.Errorf("Reset cleared the tolerance setting: %v", ld.tol)
+	}
+
+	// Refit on two classes of two features
+	x := mat.NewDense(6, 2, []float64{
+		1.0, 2.0,
+		1.5, 1.8,
+		1.2, 2.4,
+		9.0, 1.0,
+		8.5, 1.5,
+		8.8, 0.6,
+	})
+	y := []int{0, 0, 0, 1, 1, 1}
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	if ld.NumClasses() != 2 {
+		t.Errorf("unexpected number of classes got:%d, want:2", ld.NumClasses())
+	}
+	for i, want := range y {
+		if got, err := ld.Predict(x.RawRowView(i)); err != nil || got != want {
+			t.Errorf("unexpected prediction for row %d got:%d (%v), want:%d", i, got, err, want)
+		}
+	}
+
+	// A failed fit doesn't leave the previous model behind
+	if err := ld.LinearDiscriminant(x, []int{0, 0, 0, 2, 2, 2}); err == nil {
+		t.Fatal("expected an error for labels {0,2}")
+	}
+	if _, err := ld.Predict(x.RawRowView(0)); err == nil {
+		t.Error("expected an error predicting after a failed fit")
+	}
+}
+
+func TestLinearDiscriminantMissingClass(t *testing.T) {
+	x := mat.NewDense(6, 2, []float64{
+		1.0, 2.0,
+		1.5, 1.8,
+		5.0, 8.0,
+		6.0, 9.0,
+		9.0, 1.0,
+		8.5, 1.5,
+	})
+	var ld LD
+	err := ld.LinearDiscriminant(x, []int{0, 0, 1, 1, 3, 3})
+	if err == nil {
+		t.Fatal("expected an error for labels {0,1,3}")
+	}
+	if want := "Missing class 2"; err.Error() != want {
+		t.Errorf("unexpected error got:%q, want:%q", err, want)
+	}
+}
+
+func TestLinearDiscriminantEmptyClass(t *testing.T) {
+	x := mat.NewDense(4, 2, []float64{
+		1.0, 2.0,
+		1.5, 1.8,
+		9.0, 1.0,
+		8.5, 1.5,
+	})
+	var ld LD
+	err := ld.LinearDiscriminant(x, []int{0, 0, 2, 2})
+	if err == nil {
+		t.Fatal("expected an error for labels {0,2}")
+	}
+	if !strings.Contains(strings.ToLower(err.Error()), "class 1") {
+		t.Errorf("error %q doesn't name the empty class 1", err)
+	}
+	if ld.ct != nil {
+		t.Errorf("unexpected constants for a failed fit: %v", ld.ct)
 	}
 }
 
-// PlotLDA plots the LDA transformation on an (X,Y) plane and returns a PNG
-// of the graph, which is saved in the same directory as the source code
-func PlotLDA(Data *mat.Dense, labels []int, imageTitle string, graphTitle string) {
-	p := plot.New()
-	p.Title.Text = graphTitle
-	p.X.Label.Text = "X"
-	p.Y.Label.Text = "Y"
+func TestLinearDiscriminantNaN(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	x := mat.DenseCopyOf(dataMatrix)
+	x.Set(17, 2, math.NaN())
+	x.Set(60, 0, math.Inf(1))
+	var ld LD
+	err := ld.LinearDiscriminant(x, labels)
+	if err == nil {
+		t.Fatal("expected an error for a NaN element")
+	}
+	if want := "Non-finite value NaN at (17, 2)"; err.Error() != want {
+		t.Errorf("unexpected error got:%q, want:%q", err, want)
+	}
 
-	scatterData := matrixToPoints(Data)
-	sc, err := plotter.NewScatter(scatterData)
+	dropped, err := ld.FitDropNaN(x, labels)
 	if err != nil {
-		log.Panic(err)
+		t.Fatal(err)
+	}
+	if dropped != 2 {
+		t.Errorf("unexpected number of dropped rows got:%d, want:2", dropped)
+	}
+	if ld.n != 148 {
+		t.Errorf("unexpected number of observations got:%d, want:148", ld.n)
+	}
+}
+
+func TestLinearDiscriminantConstantColumn(t *testing.T) {
+	x := mat.NewDense(6, 3, []float64{
+		1.0, 2.0, 4.0,
+		1.5, 1.8, 4.0,
+		1.2, 2.4, 4.0,
+		5.0, 8.0, 4.0,
+		6.0, 9.0, 4.0,
+		5.5, 8.2, 4.0,
+	})
+	y := []int{0, 0, 0, 1, 1, 1}
+	var ld LD
+	err := ld.LinearDiscriminant(x, y)
+	if err == nil {
+		t.Fatal("expected an error for a constant column")
+	}
+	if want := "Covariance matrix (column 2) is close to singular"; err.Error() != want {
+		t.Errorf("unexpected error got:%q, want:%q", err, want)
+	}
+
+	if err := ld.SetTol(-1); err == nil {
+		t.Error("expected an error setting a negative tol")
+	}
+	if err := ld.SetTol(10); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.LinearDiscriminant(x, y); err == nil {
+		t.Error("expected an error with a tol larger than every variance")
+	}
+}
+
+func TestPriors(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if ld.Priors() != nil {
+		t.Error("expected nil priors for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
 	}
+	priors := ld.Priors()
+	if len(priors) != 3 {
+		t.Fatalf("unexpected number of priors got:%d, want:3", len(priors))
+	}
+	var sum float64
+	for i, p := range priors {
+		if math.Abs(p-1.0/3) > 1e-12 {
+			t.Errorf("unexpected prior %d got:%v, want:1/3", i, p)
+		}
+		sum += p
+	}
+	if math.Abs(sum-1) > 1e-12 {
+		t.Errorf("unexpected sum of priors got:%v, want:1", sum)
+	}
+
+	custom := []float64{0.5, 0.3, 0.2}
+	if err := ld.SetPriors(custom); err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range ld.Priors() {
+		if math.Abs(p-custom[i]) > 1e-12 {
+			t.Errorf("unexpected custom prior %d got:%v, want:%v", i, p, custom[i])
+		}
+	}
+}
 
-	sc.GlyphStyleFunc = func(i int) draw.GlyphStyle {
-		r := (map[bool]uint8{true: 128, false: 0})[labels[i]&(1<<2) != 0]
-		g := (map[bool]uint8{true: 128, false: 0})[labels[i]&(1<<1) != 0]
-		b := (map[bool]uint8{true: 128, false: 0})[labels[i]&1 != 0]
-		a := uint8(255)
-		color := color.RGBA{r, g, b, a}
-		markers := [7]draw.GlyphDrawer{
-			draw.CrossGlyph{},
-			draw.CircleGlyph{},
-			draw.PyramidGlyph{},
-			draw.TriangleGlyph{},
-			draw.SquareGlyph{},
-			draw.RingGlyph{},
-			draw.PlusGlyph{},
+func TestSetPriors(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	for _, priors := range [][]float64{
+		{0.5, 0.5},
+		{0.5, 0.5, 0.5},
+		{1.2, -0.1, -0.1},
+	} {
+		if err := ld.SetPriors(priors); err == nil {
+			t.Errorf("expected an error for priors %v", priors)
 		}
-		return draw.GlyphStyle{Color: color, Radius: vg.Points(3), Shape: markers[labels[i]%7]}
 	}
-	p.Add(sc)
-	p.Add(plotter.NewGrid())
 
-	if err := p.Save(8*vg.Inch, 5*vg.Inch, imageTitle); err != nil {
-		panic(err)
+	// A sample between Versicolor (class 0) and Virginica (class 1)
+	x := []float64{6.0, 2.7, 5.1, 1.6}
+	c, err := ld.Predict(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skewed := []float64{0.01, 0.01, 0.01}
+	skewed[1-c] = 0.98
+	if err := ld.SetPriors(skewed); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ld.Predict(x); got != 1-c {
+		t.Errorf("unexpected prediction with skewed priors got:%d, want:%d", got, 1-c)
+	}
+
+	// Priors set before fitting are used by the fit
+	var prefit LD
+	if err := prefit.SetPriors(skewed); err != nil {
+		t.Fatal(err)
+	}
+	if err := prefit.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := prefit.Predict(x); got != 1-c {
+		t.Errorf("unexpected prediction with priors set before fitting got:%d, want:%d", got, 1-c)
+	}
+}
+
+// collinearData returns a three-class dataset whose third column is the sum
+// of the first two, so its within-class scatter matrix is singular.
+func collinearData() (*mat.Dense, []int) {
+	x := mat.NewDense(12, 3, nil)
+	base := [][]float64{
+		{1.0, 2.0}, {1.4, 1.7}, {0.8, 2.3}, {1.2, 2.2},
+		{5.0, 1.0}, {5.3, 1.4}, {4.7, 0.8}, {5.1, 0.6},
+		{3.0, 6.0}, {3.3, 6.4}, {2.6, 5.9}, {3.1, 5.5},
 	}
+	var y []int
+	for i, row := range base {
+		x.Set(i, 0, row[0])
+		x.Set(i, 1, row[1])
+		x.Set(i, 2, row[0]+row[1])
+		y = append(y, i/4)
+	}
+	return x, y
 }
 
-func matrixToPoints(data *mat.Dense) plotter.XYer {
-	r, c := data.Dims()
-	if c != 2 {
-		panic("Matrix must have 2 columns (2D matrix only)")
+func TestLinearDiscriminantSingular(t *testing.T) {
+	x, y := collinearData()
+	var buf bytes.Buffer
+	var ld LD
+	ld.SetDebug(&buf)
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
 	}
-	pts := make(plotter.XYs, r)
+	if !strings.Contains(buf.String(), "falling back to the pseudo-inverse") {
+		t.Errorf("expected the fallback to be logged, got:\n%s", buf.String())
+	}
+	for i, v := range ld.evals {
+		if math.IsNaN(real(v)) || math.IsInf(real(v), 0) {
+			t.Errorf("eigenvalue %d is not finite: %v", i, v)
+		}
+	}
+	for i := 0; i < ld.k; i++ {
+		if c, err := ld.Predict(ld.mu.RawRowView(i)); err != nil {
+			t.Fatal(err)
+		} else if c != i {
+			t.Errorf("unexpected prediction for the mean of class %d got:%d", i, c)
+		}
+	}
+}
+
+func TestLinearDiscriminantCollinearColumns(t *testing.T) {
+	x, y, _ := loadIris(t)
+	r, _ := x.Dims()
 	for i := 0; i < r; i++ {
-		pts[i].X = data.At(i, 0)
-		pts[i].Y = data.At(i, 1)
+		x.Set(i, 3, x.At(i, 0)+x.At(i, 1))
+	}
+	var buf bytes.Buffer
+	var ld LD
+	ld.SetDebug(&buf)
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	want := "within-class scatter matrix is singular: column 3 is a linear combination of columns [0 1]"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("expected the diagnostics to mention %q, got:\n%s", want, buf.String())
+	}
+
+	// The Cholesky solver has no fallback and reports the collinearity
+	if err := ld.SetSolver(CholeskySolver); err != nil {
+		t.Fatal(err)
+	}
+	err := ld.LinearDiscriminant(x, y)
+	if err == nil {
+		t.Fatal("expected an error for collinear features")
+	}
+	if want := "Within-class scatter matrix is singular: column 3 is a linear combination of columns [0 1]"; err.Error() != want {
+		t.Errorf("unexpected error got:%q, want:%q", err, want)
+	}
+}
+
+func TestLinearDiscriminantContext(t *testing.T) {
+	x, y, _ := syntheticData(200000, 50, 3)
+	var ld LD
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if err := ld.LinearDiscriminantContext(ctx, x, y); err != context.Canceled {
+		t.Fatalf("unexpected error got:%v, want:%v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("cancelled fit took %v", elapsed)
+	}
+	if ld.mu != nil || ld.cw != nil {
+		t.Error("cancelled fit left a partial model")
+	}
+	if _, err := ld.Predict(x.RawRowView(0)); err == nil {
+		t.Error("expected an error predicting with a cancelled fit")
+	}
+	if err := ld.LinearDiscriminantContext(context.Background(), x.Slice(0, 1000, 0, 50), y[:1000]); err != nil {
+		t.Fatal(err)
+	}
+
+	// The input isn't validated once the context is cancelled
+	if err := ld.LinearDiscriminantContext(ctx, x, nil); err != context.Canceled {
+		t.Errorf("unexpected error for invalid input got:%v, want:%v", err, context.Canceled)
+	}
+	if err := checkFinite(ctx, x); err != context.Canceled {
+		t.Errorf("unexpected error from checkFinite got:%v, want:%v", err, context.Canceled)
+	}
+}
+
+func TestTransformWhitened(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if _, err := ld.TransformWhitened(dataMatrix, 2); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	result, err := ld.TransformWhitened(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pooled within-class variance of each whitened column
+	r, c := result.Dims()
+	for j := 0; j < c; j++ {
+		sum := make([]float64, ld.k)
+		for i := 0; i < r; i++ {
+			sum[labels[i]] += result.At(i, j)
+		}
+		var ss float64
+		for i := 0; i < r; i++ {
+			d := result.At(i, j) - sum[labels[i]]/float64(ld.ni[labels[i]])
+			ss += d * d
+		}
+		if got := ss / float64(r-ld.k); math.Abs(got-1) > 1e-9 {
+			t.Errorf("unexpected within-class variance of column %d got:%v, want:1", j, got)
+		}
+	}
+}
+
+func TestCholeskySolver(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ref LD
+	if err := ref.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	var ld LD
+	if err := ld.SetSolver(CholeskySolver); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+
+	got, want := ld.EigenValues(), ref.EigenValues()
+	for i, v := range ld.evals {
+		if imag(v) != 0 {
+			t.Errorf("eigenvalue %d has an imaginary part: %v", i, v)
+		}
+		if math.Abs(got[i]-want[i]) > 1e-9*math.Max(1, math.Abs(want[i])) {
+			t.Errorf("unexpected eigenvalue %d got:%v, want:%v", i, got[i], want[i])
+		}
+	}
+	gotT, err := ld.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantT, err := ref.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mat.EqualApprox(gotT, wantT, 1e-9) {
+		t.Error("Transform differs from the inverse solver")
+	}
+	r, _ := dataMatrix.Dims()
+	for i := 0; i < r; i++ {
+		x := dataMatrix.RawRowView(i)
+		c, _ := ld.Predict(x)
+		if refC, _ := ref.Predict(x); c != refC {
+			t.Errorf("unexpected prediction for row %d got:%d, want:%d", i, c, refC)
+		}
+	}
+
+	x, y := collinearData()
+	if err := ld.LinearDiscriminant(x, y); err == nil || !strings.HasPrefix(err.Error(), "Within-class scatter matrix is singular") {
+		t.Errorf("unexpected error for collinear features %v", err)
+	}
+}
+
+func TestLinearDiscriminantOneFeature(t *testing.T) {
+	// Class means 1.75 and 6.75, so with equal priors the threshold is 4.25
+	x := mat.NewDense(8, 1, []float64{1, 2, 1.5, 2.5, 6, 7, 6.5, 7.5})
+	y := []int{0, 0, 0, 0, 1, 1, 1, 1}
+	var ld LD
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		x    float64
+		want int
+	}{
+		{-3, 0}, {1.75, 0}, {4.2, 0}, {4.3, 1}, {6.75, 1}, {20, 1},
+	} {
+		if got, err := ld.Predict([]float64{test.x}); err != nil {
+			t.Fatal(err)
+		} else if got != test.want {
+			t.Errorf("unexpected prediction for %v got:%d, want:%d", test.x, got, test.want)
+		}
+	}
+	result, err := ld.Transform(x, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 8; i++ {
+		if got, want := result.At(i, 0), x.At(i, 0)-4.25; math.Abs(got-want) > 1e-12 {
+			t.Errorf("expected the identity projection of the centered data for row %d got:%v, want:%v", i, got, want)
+		}
+	}
+	if got := ld.EigenValues(); len(got) != 1 || got[0] <= 0 {
+		t.Errorf("unexpected eigenvalues %v", got)
+	}
+}
+
+func TestSetDebug(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var buf bytes.Buffer
+	var ld LD
+	ld.SetDebug(&buf)
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"class counts [50 50 50]",
+		"condition number",
+		"eigenvalues [(",
+		"retained 2 of 4 discriminants",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected the diagnostics to mention %q, got:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	ld.SetDebug(nil)
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("unexpected diagnostics after SetDebug(nil): %s", buf.String())
+	}
+}
+
+func TestScatterConditionNumber(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if _, err := ld.ScatterConditionNumber(); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	cond, err := ld.ScatterConditionNumber()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.IsInf(cond, 0) || math.IsNaN(cond) || cond < 1 || cond > 1e3 {
+		t.Errorf("unexpected condition number for Iris got:%v", cond)
+	}
+
+	// The third column is nearly the sum of the first two, which only the
+	// SVD solver accepts
+	x, y := collinearData()
+	r, _ := x.Dims()
+	for i := 0; i < r; i++ {
+		x.Set(i, 2, x.At(i, 2)+1e-6*float64(i%3))
+	}
+	if err := ld.SetSolver(SVDSolver); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	cond, err = ld.ScatterConditionNumber()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cond < 1e9 {
+		t.Errorf("expected a very large condition number for near-collinear data got:%v", cond)
+	}
+}
+
+func TestLinearDiscriminantTwoClasses(t *testing.T) {
+	// Setosa (class 2) against Versicolor (class 0)
+	dataMatrix, labels, _ := loadIris(t)
+	var rows []float64
+	var y []int
+	for i, label := range labels {
+		if label == 1 {
+			continue
+		}
+		rows = append(rows, dataMatrix.RawRowView(i)...)
+		y = append(y, label/2)
+	}
+	x := mat.NewDense(len(y), 4, rows)
+	var ld LD
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	if len(ld.dirs) != 1 {
+		t.Errorf("unexpected number of discriminants got:%d, want:1", len(ld.dirs))
+	}
+	if _, err := ld.Transform(x, 2); err == nil {
+		t.Error("expected an error for 2 components of a two-class model")
+	}
+	result, err := ld.Transform(x, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The single component separates the classes completely
+	min := []float64{math.Inf(1), math.Inf(1)}
+	max := []float64{math.Inf(-1), math.Inf(-1)}
+	for i, c := range y {
+		v := result.At(i, 0)
+		min[c] = math.Min(min[c], v)
+		max[c] = math.Max(max[c], v)
+	}
+	if !(max[0] < min[1] || max[1] < min[0]) {
+		t.Errorf("classes overlap on the discriminant: [%v,%v] and [%v,%v]", min[0], max[0], min[1], max[1])
+	}
+	for i, want := range y {
+		if c, _ := ld.Predict(x.RawRowView(i)); c != want {
+			t.Errorf("unexpected prediction for row %d got:%d, want:%d", i, c, want)
+		}
+	}
+}
+
+func TestSVDSolver(t *testing.T) {
+	x, y := collinearData()
+	var ld LD
+	if err := ld.SetSolver(Solver(-1)); err == nil {
+		t.Error("expected an error for an invalid solver")
+	}
+	if err := ld.SetSolver(SVDSolver); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+
+	var inverse mat.Dense
+	if err := inverse.Inverse(ld.cw); err == nil {
+		t.Error("expected plain Inverse to fail on the collinear scatter matrix")
+	}
+	for i, v := range ld.evals {
+		if math.IsNaN(real(v)) || math.IsInf(real(v), 0) {
+			t.Errorf("eigenvalue %d is not finite: %v", i, v)
+		}
+	}
+	for i := 0; i < ld.k; i++ {
+		c, err := ld.Predict(ld.mu.RawRowView(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c != i {
+			t.Errorf("unexpected prediction for the mean of class %d got:%d", i, c)
+		}
+	}
+}
+
+func TestSetShrinkage(t *testing.T) {
+	// More features than samples make the within-class scatter singular
+	rnd := rand.New(rand.NewSource(1))
+	const p = 10
+	means := [][]float64{make([]float64, p), make([]float64, p), make([]float64, p)}
+	for j := 0; j < p; j++ {
+		means[1][j] = 4
+		means[2][j] = float64(4 * (j % 2))
+	}
+	x, y := gaussianClasses(rnd, means, []int{3, 3, 3})
+
+	var ld LD
+	for _, lambda := range []float64{-0.1, 1.1} {
+		if err := ld.SetShrinkage(lambda); err == nil {
+			t.Errorf("expected an error for shrinkage %v", lambda)
+		}
+	}
+	var buf bytes.Buffer
+	ld.SetDebug(&buf)
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "falling back to the pseudo-inverse") {
+		t.Error("expected the unregularized singular scatter matrix to need the pseudo-inverse")
+	}
+	buf.Reset()
+
+	if err := ld.SetShrinkage(0.5); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "falling back") {
+		t.Error("expected the shrunk scatter matrix to be invertible")
+	}
+	var inverse mat.Dense
+	if err := inverse.Inverse(ld.shrunkCovariance(ld.pooledCovariance())); err != nil {
+		t.Errorf("unexpected error inverting the shrunk covariance matrix: %v", err)
+	}
+	for i, v := range ld.evals {
+		if math.IsNaN(real(v)) || math.IsInf(real(v), 0) {
+			t.Errorf("eigenvalue %d is not finite: %v", i, v)
+		}
+	}
+	for i := 0; i < ld.k; i++ {
+		c, err := ld.Predict(ld.mu.RawRowView(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c != i {
+			t.Errorf("unexpected prediction for the mean of class %d got:%d", i, c)
+		}
+	}
+}
+
+func TestSetDiagonalCovariance(t *testing.T) {
+	// More features than samples make the full covariance unestimable
+	rnd := rand.New(rand.NewSource(1))
+	const p = 20
+	means := [][]float64{make([]float64, p), make([]float64, p)}
+	for j := 0; j < p; j++ {
+		means[1][j] = 3
+	}
+	x, y := gaussianClasses(rnd, means, []int{5, 5})
+
+	var buf bytes.Buffer
+	var ld LD
+	ld.SetDebug(&buf)
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "falling back to the pseudo-inverse") {
+		t.Error("expected the full covariance to need the pseudo-inverse")
+	}
+	ld.SetDiagonalCovariance(true)
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < ld.k; i++ {
+		c, err := ld.Predict(ld.mu.RawRowView(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c != i {
+			t.Errorf("unexpected prediction for the mean of class %d got:%d", i, c)
+		}
+	}
+}
+
+func TestFitRemap(t *testing.T) {
+	x := mat.NewDense(6, 2, []float64{
+		1.0, 2.0,
+		1.5, 1.8,
+		9.0, 1.0,
+		8.5, 1.5,
+		1.2, 2.4,
+		8.8, 0.6,
+	})
+	y := []int{5, 5, 9, 9, 5, 9}
+	var ld LD
+	if err := ld.LinearDiscriminant(x, y); err == nil {
+		t.Error("expected an error for labels {5,9} without remapping")
+	}
+	if err := ld.FitRemap(x, y); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range y {
+		got, err := ld.Predict(x.RawRowView(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("unexpected prediction for row %d got:%d, want:%d", i, got, want)
+		}
+	}
+
+	// Refitting with dense labels drops the mapping
+	if err := ld.LinearDiscriminant(x, []int{0, 0, 1, 1, 0, 1}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ld.Predict(x.RawRowView(2)); got != 1 {
+		t.Errorf("unexpected prediction after refitting got:%d, want:1", got)
+	}
+}
+
+func TestFitLabels(t *testing.T) {
+	dataMatrix, _, species := loadIris(t)
+	var ld LD
+	if err := ld.FitLabels(dataMatrix, species); err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range []struct {
+		x    []float64
+		want string
+	}{
+		{x: []float64{5.0, 3.3, 1.4, 0.2}, want: "Iris-setosa"},
+		{x: []float64{5.1, 2.5, 3.0, 1.1}, want: "Iris-versicolor"},
+		{x: []float64{7.7, 3.0, 6.1, 2.3}, want: "Iris-virginica"},
+	} {
+		got, err := ld.PredictLabel(test.x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("unexpected prediction result %d got:%s, want:%s", i, got, test.want)
+		}
+	}
+
+	var intLD LD
+	dataMatrix, labels, _ := loadIris(t)
+	if err := intLD.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := intLD.PredictLabel([]float64{5.0, 3.3, 1.4, 0.2}); err == nil {
+		t.Error("expected an error predicting a label without string labels")
+	}
+}
+
+func TestDecisionFunction(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ld.DecisionFunction([]float64{1, 2}); err == nil {
+		t.Error("expected an error for a short input vector")
+	}
+	for i, x := range [][]float64{
+		{5.0, 3.3, 1.4, 0.2}, // Setosa
+		{5.1, 2.5, 3.0, 1.1}, // Versicolor
+		{7.7, 3.0, 6.1, 2.3}, // Virginica
+	} {
+		scores, err := ld.DecisionFunction(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(scores) != ld.k {
+			t.Fatalf("unexpected number of scores got:%d, want:%d", len(scores), ld.k)
+		}
+		best := 0
+		for j, v := range scores {
+			if v > scores[best] {
+				best = j
+			}
+		}
+		c, _ := ld.Predict(x)
+		if best != c {
+			t.Errorf("argmax of scores for sample %d got:%d, want:%d", i, best, c)
+		}
+	}
+}
+
+func TestDecisionFunctionScores(t *testing.T) {
+	// The scores scale each discriminant direction by its within-class
+	// variance, so they differ between classes exactly as the classical
+	// LDA scores ct[i] - 0.5*(x-mu[i])' S^-1 (x-mu[i]) do, with S the
+	// pooled within-class covariance
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	chol, err := ld.pooledCholesky()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, c := dataMatrix.Dims()
+	want := make([]float64, ld.k)
+	var d, sd mat.VecDense
+	for i := 0; i < r; i += 10 {
+		x := dataMatrix.RawRowView(i)
+		for j := 0; j < ld.k; j++ {
+			d.SubVec(mat.NewVecDense(c, x), ld.mu.RowView(j))
+			if err := chol.SolveVecTo(&sd, &d); err != nil {
+				t.Fatal(err)
+			}
+			want[j] = ld.ct[j] - 0.5*mat.Dot(&d, &sd)
+		}
+		got, err := ld.DecisionFunction(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j := 1; j < ld.k; j++ {
+			if diff := (got[j] - got[0]) - (want[j] - want[0]); math.Abs(diff) > 1e-9*math.Max(1, math.Abs(want[j]-want[0])) {
+				t.Errorf("unexpected score difference of class %d for row %d got:%v, want:%v", j, i, got[j]-got[0], want[j]-want[0])
+			}
+		}
+	}
+}
+
+func TestPredictConcurrent(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	r, _ := dataMatrix.Dims()
+	want := make([]int, r)
+	for i := range want {
+		want[i], _ = ld.Predict(dataMatrix.RawRowView(i))
+	}
+
+	const goroutines = 16
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < r; i++ {
+				got, err := ld.Predict(dataMatrix.RawRowView(i))
+				if err != nil {
+					errs <- err
+					return
+				}
+				if got != want[i] {
+					errs <- fmt.Errorf("unexpected prediction for row %d got:%d, want:%d", i, got, want[i])
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestPredictIgnoresNullDirections(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if len(ld.dirs) != 2 {
+		t.Fatalf("unexpected number of discriminant directions got:%d, want:2", len(ld.dirs))
+	}
+	r, _ := dataMatrix.Dims()
+	want := make([]int, r)
+	for i := range want {
+		want[i], _ = ld.Predict(dataMatrix.RawRowView(i))
+	}
+
+	// The trailing eigenvalues are rounding error; their sign and size must
+	// not affect the predictions.
+	order := discriminantOrder(ld.evals)
+	for _, tiny := range []complex128{1e-15, -1e-15, 3e-13, -2e-14} {
+		for _, j := range order[2:] {
+			ld.evals[j] = tiny
+			ld.wvar[j] = cmplx.Abs(tiny)
+		}
+		for i := range want {
+			if got, _ := ld.Predict(dataMatrix.RawRowView(i)); got != want[i] {
+				t.Errorf("unexpected prediction for row %d with trailing eigenvalue %v got:%d, want:%d", i, tiny, got, want[i])
+			}
+		}
+	}
+}
+
+func TestPredictWithMargin(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ld.PredictWithMargin([]float64{1}); err == nil {
+		t.Error("expected an error for a short input vector")
+	}
+
+	// Setosa (2) is far from the other classes
+	class, clear, err := ld.PredictWithMargin([]float64{5.0, 3.3, 1.4, 0.2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if class != 2 {
+		t.Errorf("unexpected class got:%d, want:2", class)
+	}
+	// A Versicolor sample close to the Virginica boundary
+	x := []float64{6.0, 2.7, 5.1, 1.6}
+	class, boundary, err := ld.PredictWithMargin(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := ld.Predict(x); class != want {
+		t.Errorf("unexpected class got:%d, want:%d", class, want)
+	}
+	if boundary < 0 || clear < 10*boundary {
+		t.Errorf("unexpected margins got:%v for Setosa and %v at the boundary", clear, boundary)
+	}
+}
+
+func TestPredictWithProbabilities(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if _, _, err := ld.PredictWithProbabilities([]float64{1, 2, 3, 4}); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	r, _ := dataMatrix.Dims()
+	for i := 0; i < r; i++ {
+		x := dataMatrix.RawRowView(i)
+		class, prob, err := ld.PredictWithProbabilities(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, _ := ld.Predict(x); class != want {
+			t.Errorf("unexpected class for row %d got:%d, want:%d", i, class, want)
+		}
+		want, _ := ld.PredictProba(x)
+		for j := range want {
+			if prob[j] != want[j] {
+				t.Errorf("unexpected probability of class %d for row %d got:%v, want:%v", j, i, prob[j], want[j])
+			}
+		}
+	}
+}
+
+func TestPredictTopK(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	// A Versicolor sample close to the Virginica boundary
+	x := []float64{6.0, 2.7, 5.1, 1.6}
+	for _, topk := range []int{0, 4} {
+		if _, err := ld.PredictTopK(x, topk); err == nil {
+			t.Errorf("expected an error for top %d", topk)
+		}
+	}
+	got, err := ld.PredictTopK(x, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Versicolor (0) and Virginica (1) come before Setosa (2)
+	if len(got) != 3 || got[2] != 2 || got[0]+got[1] != 1 {
+		t.Errorf("unexpected class order got:%v", got)
+	}
+	best, _ := ld.Predict(x)
+	if got[0] != best {
+		t.Errorf("unexpected top class got:%d, want:%d", got[0], best)
+	}
+	if top1, _ := ld.PredictTopK(x, 1); len(top1) != 1 || top1[0] != best {
+		t.Errorf("unexpected top 1 got:%v, want:[%d]", top1, best)
+	}
+}
+
+func TestPredictNearestMean(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if _, err := ld.PredictNearestMean([]float64{5.0, 3.3, 1.4, 0.2}, 1); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ld.PredictNearestMean([]float64{5.0}, 1); err == nil {
+		t.Error("expected an error for a short input vector")
+	}
+	if _, err := ld.PredictNearestMean([]float64{5.0, 3.3, 1.4, 0.2}, 3); err == nil {
+		t.Error("expected an error for too many dimensions")
+	}
+	for _, x := range [][]float64{
+		{5.0, 3.3, 1.4, 0.2}, // Setosa
+		{5.1, 2.5, 3.0, 1.1}, // Versicolor
+		{7.7, 3.0, 6.1, 2.3}, // Virginica
+	} {
+		want, err := ld.Predict(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for n := 1; n <= 2; n++ {
+			got, err := ld.PredictNearestMean(x, n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("unexpected prediction for %v in %d dimensions got:%d, want:%d", x, n, got, want)
+			}
+		}
+	}
+}
+
+func TestTransformAndPredict(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	setosa := []float64{5.0, 3.3, 1.4, 0.2}
+	if _, _, err := ld.TransformAndPredict(setosa, 2); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ld.TransformAndPredict(setosa[:3], 2); err == nil {
+		t.Error("expected an error for a short input vector")
+	}
+	if _, _, err := ld.TransformAndPredict(setosa, 3); err == nil {
+		t.Error("expected an error for too many dimensions")
+	}
+	for n := 1; n <= 2; n++ {
+		coords, class, err := ld.TransformAndPredict(setosa, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Setosa is class 2 in order of first appearance
+		if class != 2 {
+			t.Errorf("unexpected class got:%d, want:2", class)
+		}
+		if len(coords) != n {
+			t.Fatalf("unexpected number of coordinates got:%d, want:%d", len(coords), n)
+		}
+		want, err := ld.Transform(mat.NewDense(1, 4, setosa), n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for c, v := range coords {
+			if math.Abs(v-want.At(0, c)) > 1e-12 {
+				t.Errorf("unexpected coordinate %d got:%v, want:%v", c, v, want.At(0, c))
+			}
+		}
+	}
+}
+
+func TestMahalanobisDistances(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ld.MahalanobisDistances([]float64{1, 2}); err == nil {
+		t.Error("expected an error for a short input vector")
+	}
+
+	// Setosa is class 2
+	dists, err := ld.MahalanobisDistances([]float64{5.0, 3.3, 1.4, 0.2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dists) != 3 {
+		t.Fatalf("unexpected number of distances got:%d, want:3", len(dists))
+	}
+	for i, d := range dists {
+		if d < 0 {
+			t.Errorf("negative distance to class %d: %v", i, d)
+		}
+		if i != 2 && d <= dists[2] {
+			t.Errorf("class %d is closer than Setosa: %v", i, dists)
+		}
+	}
+
+	// The distance of a class mean from itself is zero
+	for i := 0; i < ld.k; i++ {
+		dists, err := ld.MahalanobisDistances(ld.mu.RawRowView(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dists[i] > 1e-9 {
+			t.Errorf("unexpected distance of class %d from its mean: %v", i, dists[i])
+		}
+	}
+}
+
+func TestPredictProba(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	const epsilon = 1e-12
+	for i, x := range [][]float64{
+		{5.0, 3.3, 1.4, 0.2}, // Setosa
+		{5.1, 2.5, 3.0, 1.1}, // Versicolor
+		{7.7, 3.0, 6.1, 2.3}, // Virginica
+	} {
+		prob, err := ld.PredictProba(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(prob) != ld.k {
+			t.Fatalf("unexpected number of probabilities got:%d, want:%d", len(prob), ld.k)
+		}
+		var sum float64
+		best := 0
+		for j, v := range prob {
+			sum += v
+			if v > prob[best] {
+				best = j
+			}
+		}
+		if math.Abs(sum-1) > epsilon {
+			t.Errorf("probabilities for sample %d sum to %v, want 1", i, sum)
+		}
+		c, _ := ld.Predict(x)
+		if best != c {
+			t.Errorf("argmax of probabilities for sample %d got:%d, want:%d", i, best, c)
+		}
+	}
+}
+
+func TestBenchmarkPredict(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if _, err := ld.BenchmarkPredict(10); err == nil {
+		t.Error("expected an error benchmarking an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ld.BenchmarkPredict(0); err == nil {
+		t.Error("expected an error for zero samples")
+	}
+	perCall, err := ld.BenchmarkPredict(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perCall <= 0 || perCall > time.Millisecond {
+		t.Errorf("unexpected per-call latency %v", perCall)
+	}
+}
+
+func BenchmarkPredict(b *testing.B) {
+	dataMatrix, labelsNumbers, _ := loadIris(b)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labelsNumbers); err != nil {
+		b.Fatal(err)
+	}
+	x := []float64{7.7, 3.0, 6.1, 2.3}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ld.Predict(x); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func checkError(message string, err error) {
+	if err != nil {
+		log.Fatal(message, err)
+	}
+}
+
+func TestMaxImaginaryComponent(t *testing.T) {
+	var ld LD
+	if v := ld.MaxImaginaryComponent(); v != 0 {
+		t.Errorf("unexpected imaginary component of an unfitted model got:%v, want:0", v)
+	}
+	dataMatrix, labels, _ := loadIris(t)
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if v := ld.MaxImaginaryComponent(); v > 1e-10 {
+		t.Errorf("unexpected imaginary component for Iris got:%v, want:~0", v)
+	}
+
+	// A between-class matrix that isn't a scatter matrix has complex
+	// eigenvalues 1±2i
+	ld = LD{
+		n: 12, p: 2, k: 3,
+		ni: []int{4, 4, 4},
+		cw: mat.NewSymDense(2, []float64{9, 0, 0, 9}),
+		cb: mat.NewDense(2, 2, []float64{1, -2, 2, 1}),
+	}
+	if err := ld.solve(); err != nil {
+		t.Fatal(err)
+	}
+	if v := ld.MaxImaginaryComponent(); math.Abs(v-2) > 1e-10 {
+		t.Errorf("unexpected imaginary component got:%v, want:2", v)
+	}
+}
+
+func TestSetClassWeights(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	// pairSeparation returns the Fisher ratio of Versicolor (0) and
+	// Virginica (1) along the first discriminant
+	pairSeparation := func(ld *LD) float64 {
+		w := ld.svecs.ColView(0)
+		var d mat.VecDense
+		d.SubVec(ld.mu.RowView(0), ld.mu.RowView(1))
+		between := mat.Dot(w, &d)
+		return between * between / mat.Inner(w, ld.pooledCovariance(), w)
+	}
+
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	unweighted := pairSeparation(&ld)
+
+	for _, weights := range [][]float64{{1, 1}, {1, 0, 1}, {1, -1, 1}, {1, math.Inf(1), 1}} {
+		if err := ld.SetClassWeights(weights); err == nil {
+			t.Errorf("expected an error for class weights %v", weights)
+		}
+	}
+	if err := ld.SetClassWeights([]float64{1, 1, 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if got := pairSeparation(&ld); math.Abs(got-unweighted) > 1e-8*unweighted {
+		t.Errorf("unexpected separation with equal weights got:%v, want:%v", got, unweighted)
+	}
+
+	if err := ld.SetClassWeights([]float64{20, 20, 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if weighted := pairSeparation(&ld); weighted <= unweighted {
+		t.Errorf("expected up-weighting Versicolor and Virginica to separate them better got:%v, unweighted:%v", weighted, unweighted)
+	}
+
+	// The weights are kept across fits and must match the number of classes
+	x, y, _ := syntheticData(200, 3, 2)
+	if err := ld.LinearDiscriminant(x, y); err == nil {
+		t.Error("expected an error for 3 class weights with 2 classes")
+	}
+	if err := ld.SetClassWeights(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if got := pairSeparation(&ld); math.Abs(got-unweighted) > 1e-8*unweighted {
+		t.Errorf("unexpected separation after clearing the weights got:%v, want:%v", got, unweighted)
+	}
+}
+
+func TestSingleSampleClass(t *testing.T) {
+	x := mat.NewDense(6, 2, []float64{
+		1, 2,
+		2, 1,
+		3, 3,
+		9, 8,
+		8, 9,
+		5, 0,
+	})
+	y := []int{0, 0, 0, 1, 1, 2}
+	want := "Class 2 has only one sample, at least 2 per class are needed to estimate the within-class variance"
+	var ld LD
+	if err := ld.LinearDiscriminant(x, y); err == nil || err.Error() != want {
+		t.Errorf("unexpected error got:%v, want:%s", err, want)
+	}
+	if err := ld.PartialFit(x, y); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.Finalize(); err == nil || err.Error() != want {
+		t.Errorf("unexpected error from Finalize got:%v, want:%s", err, want)
+	}
+
+	// A second sample of class 2 is enough
+	x = mat.NewDense(7, 2, append(x.RawMatrix().Data, 4, 1))
+	y = append(y, 2)
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUpdatePriors(t *testing.T) {
+	var ld LD
+	if err := ld.UpdatePriors([]float64{0.5, 0.5}); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	dataMatrix, labels, _ := loadIris(t)
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	for _, priors := range [][]float64{{0.5, 0.5}, {0.5, 0.5, 0.5}, {1.2, -0.1, -0.1}} {
+		if err := ld.UpdatePriors(priors); err == nil {
+			t.Errorf("expected an error for priors %v", priors)
+		}
+	}
+	before, err := ld.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _ := dataMatrix.Dims()
+	predict := func() []int {
+		classes := make([]int, r)
+		for i := range classes {
+			if classes[i], err = ld.Predict(dataMatrix.RawRowView(i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return classes
+	}
+	original := predict()
+
+	// Strongly favoring Virginica (class 1) moves the borderline Versicolor
+	// samples over to it, and never away from it
+	if err := ld.UpdatePriors([]float64{1e-6, 1 - 2e-6, 1e-6}); err != nil {
+		t.Fatal(err)
+	}
+	updated := predict()
+	var moved int
+	for i := range updated {
+		if updated[i] != original[i] {
+			if updated[i] != 1 {
+				t.Errorf("unexpected prediction for row %d got:%d, want:1", i, updated[i])
+			}
+			moved++
+		}
+	}
+	if moved == 0 {
+		t.Error("expected some predictions to move to Virginica")
+	}
+	after, err := ld.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mat.Equal(before, after) {
+		t.Error("updating the priors changed the projection")
+	}
+}
+
+func TestFitTransformOnly(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var full LD
+	want, err := full.FitTransform(dataMatrix, labels, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ld LD
+	got, err := ld.FitTransformOnly(dataMatrix, labels, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mat.EqualApprox(got, want, 1e-12) {
+		t.Error("unexpected projection from FitTransformOnly")
+	}
+	if ld.ct != nil || ld.scoreMean != nil {
+		t.Error("expected no classifier state after FitTransformOnly")
+	}
+	x := dataMatrix.RawRowView(0)
+	if _, err := ld.Predict(x); err == nil || err.Error() != "Model fitted for transform only" {
+		t.Errorf("unexpected error from Predict got:%v", err)
+	}
+	if _, err := ld.ReducedPredict(x); err == nil {
+		t.Error("expected an error from ReducedPredict")
+	}
+	if err := ld.UpdatePriors([]float64{0.2, 0.3, 0.5}); err == nil {
+		t.Error("expected an error from UpdatePriors")
+	}
+	const transformOnly = "Model fitted for transform only"
+	if _, err := ld.PredictTopK(x, 1); err == nil || err.Error() != transformOnly {
+		t.Errorf("unexpected error from PredictTopK got:%v, want:%s", err, transformOnly)
+	}
+	if _, err := ld.EstimatePriorsEM(dataMatrix, 10); err == nil || err.Error() != transformOnly {
+		t.Errorf("unexpected error from EstimatePriorsEM got:%v, want:%s", err, transformOnly)
+	}
+
+	// A full fit makes the model a classifier again
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ld.Predict(x); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSetRidge(t *testing.T) {
+	var ld LD
+	for _, alpha := range []float64{-1e-3, math.Inf(1), math.NaN()} {
+		if err := ld.SetRidge(alpha); err == nil {
+			t.Errorf("expected an error for ridge %v", alpha)
+		}
+	}
+
+	// The third feature is the sum of the other two, so the covariance matrix
+	// can't be factorized. CholeskySolver has no pseudo-inverse fallback.
+	x, y := collinearData()
+	if err := ld.SetSolver(CholeskySolver); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.LinearDiscriminant(x, y); err == nil {
+		t.Fatal("expected an error for a singular covariance matrix without a ridge")
+	}
+	if err := ld.SetRidge(1e-2); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range ld.EigenValues() {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			t.Errorf("eigenvalue %d is not finite: %v", i, v)
+		}
+	}
+	r, _ := x.Dims()
+	for i := 0; i < r; i++ {
+		c, err := ld.Predict(x.RawRowView(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c != y[i] {
+			t.Errorf("unexpected prediction for row %d got:%d, want:%d", i, c, y[i])
+		}
+	}
+
+	// A ridge of 0 leaves the fit unchanged
+	dataMatrix, labels, _ := loadIris(t)
+	var plain LD
+	if err := plain.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	ld = LD{}
+	if err := ld.SetRidge(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	want, got := plain.EigenValues(), ld.EigenValues()
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("unexpected eigenvalue %d with a ridge of 0 got:%v, want:%v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestProjectedClassMeans(t *testing.T) {
+	var ld LD
+	if _, err := ld.ProjectedClassMeans(2); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	dataMatrix, labels, _ := loadIris(t)
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ld.ProjectedClassMeans(3); err == nil {
+		t.Error("expected an error for more dimensions than discriminants")
+	}
+	centroids, err := ld.ProjectedClassMeans(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, c := centroids.Dims(); r != 3 || c != 2 {
+		t.Fatalf("unexpected centroid matrix size got:%d×%d, want:3×2", r, c)
+	}
+	projected, err := ld.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each centroid is the mean of its class's projected points
+	want := mat.NewDense(3, 2, nil)
+	counts := make([]float64, 3)
+	for i, label := range labels {
+		counts[label]++
+		for c := 0; c < 2; c++ {
+			want.Set(label, c, want.At(label, c)+projected.At(i, c))
+		}
+	}
+	for i := 0; i < 3; i++ {
+		for c := 0; c < 2; c++ {
+			want.Set(i, c, want.At(i, c)/counts[i])
+		}
+	}
+	if !mat.EqualApprox(centroids, want, 1e-9) {
+		t.Errorf("unexpected centroids got:%v, want:%v", mat.Formatted(centroids), mat.Formatted(want))
+	}
+}
+
+func TestEigenFactorizationFailure(t *testing.T) {
+	// Between-class scatter that overflows makes the matrix to factorize
+	// infinite, which must fail the fit rather than hang or panic later
+	ld := LD{
+		n: 12, p: 2, k: 3,
+		ni: []int{4, 4, 4},
+		cw: mat.NewSymDense(2, []float64{9, 0, 0, 9}),
+		cb: mat.NewDense(2, 2, []float64{math.Inf(1), 1, 1, 1}),
+	}
+	for _, solver := range []Solver{InverseSolver, SVDSolver, CholeskySolver} {
+		ld.solver = solver
+		if err := ld.solve(); err == nil {
+			t.Fatalf("expected an error for a matrix that can't be factorized with solver %v", solver)
+		}
+	}
+
+	// The same through LinearDiscriminant: class means 1e160 apart overflow
+	// the between-class scatter while the within-class scatter is finite
+	x := mat.NewDense(6, 2, []float64{
+		0, 1,
+		1, 0,
+		-1, -1,
+		1e160, 1e160 + 1,
+		1e160 + 1, 1e160,
+		1e160 - 1, 1e160 - 1,
+	})
+	ld = LD{}
+	err := ld.LinearDiscriminant(x, []int{0, 0, 0, 1, 1, 1})
+	if err == nil {
+		t.Fatal("expected an error for an overflowing between-class scatter")
+	}
+	if _, err := ld.Transform(x, 1); err == nil {
+		t.Error("expected Transform to fail cleanly after a failed fit")
+	}
+	if _, err := ld.Predict(x.RawRowView(0)); err == nil {
+		t.Error("expected Predict to fail cleanly after a failed fit")
+	}
+}
+
+func TestIsFitted(t *testing.T) {
+	var ld LD
+	if ld.IsFitted() {
+		t.Error("expected a zero LD to be unfitted")
+	}
+	x := []float64{7.7, 3.0, 6.1, 2.3}
+	const want = "Model has not been fit"
+	if _, err := ld.Predict(x); err == nil || err.Error() != want {
+		t.Errorf("unexpected error from Predict got:%v, want:%s", err, want)
+	}
+	dataMatrix, labels, _ := loadIris(t)
+	if _, err := ld.Transform(dataMatrix, 2); err == nil || err.Error() != want {
+		t.Errorf("unexpected error from Transform got:%v, want:%s", err, want)
+	}
+	if _, _, err := ld.PredictBatchWithScores(dataMatrix); err == nil || err.Error() != want {
+		t.Errorf("unexpected error from PredictBatchWithScores got:%v, want:%s", err, want)
+	}
+	if _, err := ld.ReducedPredict(x); err == nil || err.Error() != want {
+		t.Errorf("unexpected error from ReducedPredict got:%v, want:%s", err, want)
+	}
+	if _, err := ld.PredictTopK(x, 1); err == nil || err.Error() != want {
+		t.Errorf("unexpected error from PredictTopK got:%v, want:%s", err, want)
+	}
+
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if !ld.IsFitted() {
+		t.Error("expected the model to be fitted")
+	}
+	if _, err := ld.Predict(x); err != nil {
+		t.Error(err)
+	}
+
+	// A failed fit leaves the model unfitted
+	if err := ld.LinearDiscriminant(dataMatrix, make([]int, len(labels))); err == nil {
+		t.Fatal("expected an error for a single class")
+	}
+	if ld.IsFitted() {
+		t.Error("expected a failed fit to leave the model unfitted")
+	}
+	if _, err := ld.Predict(x); err == nil || err.Error() != want {
+		t.Errorf("unexpected error from Predict after a failed fit got:%v, want:%s", err, want)
+	}
+	if _, err := ld.TransformWhitened(dataMatrix, 2); err == nil {
+		t.Error("expected an error from TransformWhitened after a failed fit")
+	}
+	if _, err := ld.EstimatePriorsEM(dataMatrix, 10); err == nil || err.Error() != want {
+		t.Errorf("unexpected error from EstimatePriorsEM after a failed fit got:%v, want:%s", err, want)
+	}
+	if _, err := ld.Checkpoint(); err == nil || err.Error() != want {
+		t.Errorf("unexpected error from Checkpoint after a failed fit got:%v, want:%s", err, want)
+	}
+	if err := ld.AddSample(x, 0); err == nil || err.Error() != want {
+		t.Errorf("unexpected error from AddSample after a failed fit got:%v, want:%s", err, want)
 	}
-	return pts
 }
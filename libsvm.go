@@ -0,0 +1,67 @@
+package lda
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// LoadLibSVM reads observations in the sparse libsvm format, one per line:
+//
+//	label index:value index:value ...
+//
+// Indices start from 1, and features that are left out are zero. Blank
+// lines are skipped.
+//
+// Parameter r is the source of the libsvm data.
+// Parameter numFeatures is the number of features p. Every index must be in
+// [1,p].
+// Returns the n×p matrix of features and the label of each row as written.
+// Labels such as -1 and +1 don't start from zero, so fit them with FitRemap.
+func LoadLibSVM(r io.Reader, numFeatures int) (*mat.Dense, []int, error) {
+	if numFeatures < 1 {
+		return nil, nil, fmt.Errorf("Invalid number of features %d", numFeatures)
+	}
+	scanner := bufio.NewScanner(r)
+	var data []float64
+	var y []int
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		label, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("Line %d: invalid label %q", line, fields[0])
+		}
+		row := make([]float64, numFeatures)
+		for _, token := range fields[1:] {
+			colon := strings.IndexByte(token, ':')
+			if colon < 0 {
+				return nil, nil, fmt.Errorf("Line %d: invalid token %q, want index:value", line, token)
+			}
+			index, err := strconv.Atoi(token[:colon])
+			if err != nil || index < 1 || index > numFeatures {
+				return nil, nil, fmt.Errorf("Line %d: invalid index in %q", line, token)
+			}
+			v, err := strconv.ParseFloat(token[colon+1:], 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Line %d: invalid value in %q", line, token)
+			}
+			row[index-1] = v
+		}
+		data = append(data, row...)
+		y = append(y, label)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(y) == 0 {
+		return nil, nil, fmt.Errorf("No data to analyze")
+	}
+	return mat.NewDense(len(y), numFeatures, data), y, nil
+}
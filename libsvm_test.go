@@ -0,0 +1,48 @@
+package lda
+
+import (
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestLoadLibSVM(t *testing.T) {
+	const data = `+1 1:0.5 3:-2
+-1 2:1.25
+
+0 1:3 2:4 3:5e-1
+`
+	x, y, err := LoadLibSVM(strings.NewReader(data), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := mat.NewDense(3, 3, []float64{
+		0.5, 0, -2,
+		0, 1.25, 0,
+		3, 4, 0.5,
+	})
+	if !mat.Equal(x, want) {
+		t.Errorf("unexpected matrix got:%v, want:%v", mat.Formatted(x), mat.Formatted(want))
+	}
+	if len(y) != 3 || y[0] != 1 || y[1] != -1 || y[2] != 0 {
+		t.Errorf("unexpected labels got:%v, want:[1 -1 0]", y)
+	}
+
+	for _, bad := range []string{
+		"a 1:0.5",
+		"1 1=0.5",
+		"1 0:0.5",
+		"1 4:0.5",
+		"1 x:0.5",
+		"1 1:abc",
+		"",
+	} {
+		if _, _, err := LoadLibSVM(strings.NewReader(bad), 3); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+	if _, _, err := LoadLibSVM(strings.NewReader(data), 0); err == nil {
+		t.Error("expected an error for no features")
+	}
+}
@@ -0,0 +1,75 @@
+package lda
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Options holds the settings of a fit, as an alternative to calling the
+// setters one by one before LinearDiscriminant. The zero value gives the
+// default settings.
+type Options struct {
+	Tol          float64   // Tolerance for rejecting low-variance variables, see SetTol; 0 uses the default
+	Priors       []float64 // Priori probability of each class, see SetPriors; nil uses the class frequencies
+	ClassWeights []float64 // Weight of each class in the between-class scatter, see SetClassWeights
+	Solver       Solver    // Method of solving the eigenvalue problem, see SetSolver
+	Shrinkage    float64   // Shrinkage of the within-class scatter towards the identity in [0,1], see SetShrinkage
+	Ridge        float64   // Ridge added to the within-class covariance, see SetRidge
+	Diagonal     bool      // Use only the diagonal of the within-class scatter, see SetDiagonalCovariance
+	Standardize  bool      // Z-score the features before fitting, see SetStandardize
+}
+
+// LinearDiscriminantWithOptions performs linear discriminant analysis like
+// LinearDiscriminant with the settings in opts, which replace any settings
+// made earlier with the setters. Only the writer set by SetDebug is kept.
+// For example, when there are many features relative to the number of
+// samples, Options{Shrinkage: 0.1} regularizes the singular within-class
+// scatter matrix as in shrinkage LDA.
+//
+// If any of the options is invalid, including priors or class weights whose
+// length doesn't match the number of classes in y, an error is returned and
+// ld is left unchanged. If the fit itself fails, ld is left unfitted with the
+// new settings, as with LinearDiscriminant.
+func (ld *LD) LinearDiscriminantWithOptions(x mat.Matrix, y []int, opts Options) error {
+	settings := LD{logger: ld.logger}
+	if opts.Tol != 0 {
+		if err := settings.SetTol(opts.Tol); err != nil {
+			return err
+		}
+	}
+	if opts.Priors != nil {
+		if err := settings.SetPriors(opts.Priors); err != nil {
+			return err
+		}
+	}
+	if err := settings.SetClassWeights(opts.ClassWeights); err != nil {
+		return err
+	}
+	if err := settings.SetSolver(opts.Solver); err != nil {
+		return err
+	}
+	if err := settings.SetShrinkage(opts.Shrinkage); err != nil {
+		return err
+	}
+	if err := settings.SetRidge(opts.Ridge); err != nil {
+		return err
+	}
+	settings.SetDiagonalCovariance(opts.Diagonal)
+	settings.SetStandardize(opts.Standardize)
+
+	// The lengths of the priors and class weights are only checked by the
+	// fit, after ld has been replaced
+	n, _ := x.Dims()
+	if k, err := classLabels(y, n); err == nil {
+		if opts.Priors != nil && len(opts.Priors) != k {
+			return fmt.Errorf("Got %d priors for %d classes", len(opts.Priors), k)
+		}
+		if opts.ClassWeights != nil && len(opts.ClassWeights) != k {
+			return fmt.Errorf("Got %d class weights for %d classes", len(opts.ClassWeights), k)
+		}
+	}
+
+	*ld = settings
+	return ld.LinearDiscriminant(x, y)
+}
@@ -0,0 +1,93 @@
+package lda
+
+import (
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestLinearDiscriminantWithOptions(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var plain LD
+	if err := plain.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+
+	// Zero options are the defaults, and replace earlier settings
+	var ld LD
+	if err := ld.SetShrinkage(0.5); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.LinearDiscriminantWithOptions(dataMatrix, labels, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if ld.shrink != 0 {
+		t.Errorf("unexpected shrinkage got:%v, want:0", ld.shrink)
+	}
+	want, _ := plain.Transform(dataMatrix, 2)
+	got, err := ld.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mat.Equal(got, want) {
+		t.Error("unexpected transform with zero options")
+	}
+
+	for _, opts := range []Options{
+		{Tol: -1},
+		{Priors: []float64{0.5, 0.6, 0.1}},
+		{ClassWeights: []float64{1, 0, 1}},
+		{Solver: Solver(7)},
+		{Shrinkage: 1.5},
+		{Ridge: -1},
+		{Priors: []float64{0.5, 0.5}},
+		{ClassWeights: []float64{1, 1, 1, 1}},
+	} {
+		if err := ld.LinearDiscriminantWithOptions(dataMatrix, labels, opts); err == nil {
+			t.Errorf("expected an error for options %+v", opts)
+		}
+	}
+	if !ld.IsFitted() {
+		t.Error("expected invalid options to leave the model unchanged")
+	}
+
+	// Shrinkage makes a fit with more features than samples per class
+	// invertible, and matches the setters
+	rnd := rand.New(rand.NewSource(1))
+	const p = 10
+	means := [][]float64{make([]float64, p), make([]float64, p), make([]float64, p)}
+	for j := 0; j < p; j++ {
+		means[1][j] = 4
+		means[2][j] = float64(4 * (j % 2))
+	}
+	x, y := gaussianClasses(rnd, means, []int{3, 3, 3})
+	opts := Options{Shrinkage: 0.1, Priors: []float64{0.2, 0.3, 0.5}, Standardize: true}
+	if err := ld.LinearDiscriminantWithOptions(x, y, opts); err != nil {
+		t.Fatal(err)
+	}
+	var set LD
+	if err := set.SetShrinkage(0.1); err != nil {
+		t.Fatal(err)
+	}
+	if err := set.SetPriors([]float64{0.2, 0.3, 0.5}); err != nil {
+		t.Fatal(err)
+	}
+	set.SetStandardize(true)
+	if err := set.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	want, _ = set.Transform(x, 2)
+	if got, err = ld.Transform(x, 2); err != nil {
+		t.Fatal(err)
+	}
+	if !mat.Equal(got, want) {
+		t.Error("unexpected transform with shrinkage options")
+	}
+	gotPriors, wantPriors := ld.Priors(), set.Priors()
+	for i := range wantPriors {
+		if gotPriors[i] != wantPriors[i] {
+			t.Errorf("unexpected prior %d got:%v, want:%v", i, gotPriors[i], wantPriors[i])
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package lda
+
+import (
+	"context"
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// partialStats holds the class counts, class means and within-class scatter
+// matrix accumulated by PartialFit until Finalize.
+type partialStats struct {
+	p  int
+	ni []int
+	mu [][]float64
+	cw *mat.SymDense
+}
+
+// PartialFit accumulates the statistics of one chunk of training data, for
+// data that doesn't fit in memory as a single matrix. Call it once for each
+// chunk, then call Finalize to solve the eigenvalue problem. The result is
+// the same model that LinearDiscriminant would fit on all the chunks at once.
+//
+// The first call after Finalize, Reset or a successful fit starts a new
+// model. Standardization isn't supported, since it needs the statistics of
+// all the data before the first chunk is processed. The accumulated
+// statistics can be saved with Checkpoint between chunks, and resumed from
+// the model returned by RestoreCheckpoint.
+//
+// Parameter x is a chunk of input/training data. Every chunk must have the
+// same number of columns.
+// Parameter y holds the labels of the rows of x. A chunk doesn't need to
+// contain every class, but by the time Finalize is called the labels of all
+// the chunks must cover [0,k) without gaps.
+func (ld *LD) PartialFit(x mat.Matrix, y []int) error {
+	if ld.standardize {
+		return fmt.Errorf("Standardization is not supported by PartialFit")
+	}
+	n, p := x.Dims()
+	if len(y) != n {
+		return fmt.Errorf("The sizes of X and Y don't match")
+	}
+	if n == 0 {
+		return fmt.Errorf("No data to analyze")
+	}
+	if ld.partial != nil && p != ld.partial.p {
+		return fmt.Errorf("Input has %d features, earlier chunks have %d", p, ld.partial.p)
+	}
+	k := 0
+	for _, label := range y {
+		if label < 0 {
+			return fmt.Errorf("Negative class label")
+		}
+		if label >= k {
+			k = label + 1
+		}
+	}
+	if err := checkFinite(context.Background(), x); err != nil {
+		return err
+	}
+
+	// Class counts, means and within-class scatter of the chunk
+	m := make([]int, k)
+	mu := mat.NewDense(k, p, nil)
+	for i := 0; i < n; i++ {
+		m[y[i]]++
+		for j := 0; j < p; j++ {
+			mu.Set(y[i], j, mu.At(y[i], j)+x.At(i, j))
+		}
+	}
+	for c := 0; c < k; c++ {
+		if m[c] > 0 {
+			for j := 0; j < p; j++ {
+				mu.Set(c, j, mu.At(c, j)/float64(m[c]))
+			}
+		}
+	}
+	cw, err := withinScatter(context.Background(), x, y, mu)
+	if err != nil {
+		return err
+	}
+
+	if ld.partial == nil {
+		ld.Reset()
+		ld.partial = &partialStats{p: p, cw: mat.NewSymDense(p, nil)}
+	}
+	acc := ld.partial
+	for len(acc.ni) < k {
+		acc.ni = append(acc.ni, 0)
+		acc.mu = append(acc.mu, make([]float64, p))
+	}
+
+	// Merging a chunk of m samples with mean b into a class of a samples
+	// with mean a adds a·m/(a+m) (b-a)(b-a)' to the scatter
+	acc.cw.AddSym(acc.cw, cw)
+	d := make([]float64, p)
+	for c := 0; c < k; c++ {
+		if m[c] == 0 {
+			continue
+		}
+		total := float64(acc.ni[c] + m[c])
+		w := float64(acc.ni[c]) * float64(m[c]) / total
+		for j := 0; j < p; j++ {
+			d[j] = mu.At(c, j) - acc.mu[c][j]
+		}
+		for j := 0; j < p; j++ {
+			for l := 0; l <= j; l++ {
+				acc.cw.SetSym(j, l, acc.cw.At(j, l)+w*d[j]*d[l])
+			}
+			acc.mu[c][j] += d[j] * float64(m[c]) / total
+		}
+		acc.ni[c] += m[c]
+	}
+	return nil
+}
+
+// Finalize fits the model from the statistics accumulated by PartialFit.
+// It runs the same checks as LinearDiscriminant. If it fails, the
+// accumulated statistics are kept, so more chunks can be added, or the
+// statistics checkpointed, and Finalize called again.
+func (ld *LD) Finalize() (err error) {
+	acc := ld.partial
+	if acc == nil {
+		return fmt.Errorf("No data to analyze")
+	}
+	defer func() {
+		if err != nil {
+			ld.Reset()
+			ld.partial = acc
+		}
+	}()
+
+	k := len(acc.ni)
+	if k < 2 {
+		return fmt.Errorf("Only one class")
+	}
+	if err := checkClassSizes(acc.ni); err != nil {
+		return err
+	}
+	n := 0
+	for _, count := range acc.ni {
+		n += count
+	}
+
+	ld.n, ld.p, ld.k = n, acc.p, k
+	ld.ni = append([]int(nil), acc.ni...)
+	ld.mu = mat.NewDense(k, acc.p, nil)
+	for c := range acc.mu {
+		ld.mu.SetRow(c, acc.mu[c])
+	}
+	ld.cw = mat.NewSymDense(acc.p, nil)
+	ld.cw.CopySym(acc.cw)
+	if err := ld.refit(); err != nil {
+		return err
+	}
+	ld.partial = nil
+	return nil
+}
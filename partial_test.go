@@ -0,0 +1,127 @@
+package lda
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPartialFit(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var batch LD
+	if err := batch.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+
+	var ld LD
+	if err := ld.Finalize(); err == nil {
+		t.Error("expected an error finalizing without data")
+	}
+	// The Iris data is sorted by class, so the first chunk has one class
+	// and Finalize fails until the others are added
+	if err := ld.PartialFit(dataMatrix.Slice(0, 50, 0, 4), labels[:50]); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.Finalize(); err == nil {
+		t.Error("expected an error finalizing with one class")
+	}
+	if err := ld.PartialFit(mat.NewDense(2, 3, nil), []int{0, 1}); err == nil {
+		t.Error("expected an error for a chunk with a different number of columns")
+	}
+	for _, chunk := range [][2]int{{50, 110}, {110, 150}} {
+		x := dataMatrix.Slice(chunk[0], chunk[1], 0, 4)
+		if err := ld.PartialFit(x, labels[chunk[0]:chunk[1]]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ld.Finalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	const epsilon = 1e-9
+	if ld.n != batch.n || ld.k != batch.k {
+		t.Fatalf("unexpected size got:%d×%d classes, want:%d×%d", ld.n, ld.k, batch.n, batch.k)
+	}
+	if !mat.EqualApprox(ld.mu, batch.mu, epsilon) {
+		t.Error("unexpected class means")
+	}
+	if !mat.EqualApprox(ld.cw, batch.cw, epsilon) {
+		t.Error("unexpected within-class scatter")
+	}
+	for i, want := range batch.evals {
+		got := ld.evals[i]
+		if cmplx.Abs(got-want) > epsilon*math.Max(1, cmplx.Abs(want)) {
+			t.Errorf("unexpected eigenvalue %d got:%v, want:%v", i, got, want)
+		}
+	}
+	x := []float64{7.7, 3.0, 6.1, 2.3}
+	got, _ := ld.Predict(x)
+	want, _ := batch.Predict(x)
+	if got != want {
+		t.Errorf("unexpected prediction got:%d, want:%d", got, want)
+	}
+
+	// The next chunk starts a new model
+	if err := ld.PartialFit(dataMatrix.Slice(0, 10, 0, 4), labels[:10]); err != nil {
+		t.Fatal(err)
+	}
+	if ld.mu != nil || ld.partial.ni[labels[0]] != 10 {
+		t.Error("expected PartialFit after Finalize to start a new model")
+	}
+}
+
+func TestPartialFitCheckpoint(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var batch LD
+	if err := batch.SetSolver(CholeskySolver); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+
+	var ld LD
+	if err := ld.SetSolver(CholeskySolver); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.PartialFit(dataMatrix.Slice(0, 50, 0, 4), labels[:50]); err != nil {
+		t.Fatal(err)
+	}
+	// The statistics kept after a failed Finalize can still be checkpointed
+	if err := ld.Finalize(); err == nil {
+		t.Error("expected an error finalizing with one class")
+	}
+	data, err := ld.Checkpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := RestoreCheckpoint(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.solver != CholeskySolver {
+		t.Errorf("unexpected solver got:%v, want:%v", restored.solver, CholeskySolver)
+	}
+	if err := restored.PartialFit(dataMatrix.Slice(50, 150, 0, 4), labels[50:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.Finalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	const epsilon = 1e-9
+	if !mat.EqualApprox(restored.mu, batch.mu, epsilon) {
+		t.Error("unexpected class means")
+	}
+	if !mat.EqualApprox(restored.cw, batch.cw, epsilon) {
+		t.Error("unexpected within-class scatter")
+	}
+	for i, want := range batch.evals {
+		got := restored.evals[i]
+		if cmplx.Abs(got-want) > 1e-6*math.Max(1, cmplx.Abs(want)) {
+			t.Errorf("unexpected eigenvalue %d got:%v, want:%v", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package lda
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Radius range of the points drawn by PlotWithConfidence
+const (
+	minConfidenceRadius = 1
+	maxConfidenceRadius = 6
+)
+
+// PlotLDA plots a 2D LDA transformation on an (X,Y) plane and saves the
+// graph to path in the format given by its extension, such as PNG for a
+// path ending in .png.
+//
+// Parameter data is the n×2 matrix of transformed data.
+// Parameter labels holds the class of each row of data.
+// Parameter path is the file to save the graph to.
+// Parameter title is the title of the graph.
+func PlotLDA(data *mat.Dense, labels []int, path, title string) error {
+	pts, err := matrixToPoints(data)
+	if err != nil {
+		return err
+	}
+	if len(labels) != pts.Len() {
+		return fmt.Errorf("The sizes of data and labels don't match")
+	}
+	p, err := classScatterPlot(pts, func(i int) draw.GlyphStyle {
+		return PlotOptions{}.glyphStyle(labels[i])
+	})
+	if err != nil {
+		return err
+	}
+	p.Title.Text = title
+	return p.Save(8*vg.Inch, 5*vg.Inch, path)
+}
+
+// PlotOptions customizes the appearance of PlotLDAWithOptions. Zero fields
+// fall back to the defaults used by PlotLDA.
+type PlotOptions struct {
+	Colors  []color.Color      // Color of each class, reused cyclically if there are more classes
+	Markers []draw.GlyphDrawer // Marker shape of each class, reused cyclically if there are more classes
+	Radius  vg.Length          // Radius of the points, 3 points by default
+	XLabel  string             // Label of the X axis, "X" by default
+	YLabel  string             // Label of the Y axis, "Y" by default
+
+	// Centroids is a k×2 matrix of class centroids, such as the one returned
+	// by ProjectedClassMeans, drawn as large boxes in the color of each
+	// class. Row i is the centroid of class i. No centroids are drawn if nil.
+	Centroids *mat.Dense
+}
+
+// glyphStyle returns the color and marker used to draw points of the given
+// class, taking each from the options if they are set.
+func (opts PlotOptions) glyphStyle(label int) draw.GlyphStyle {
+	radius := opts.Radius
+	if radius == 0 {
+		radius = vg.Points(3)
+	}
+	style := classGlyphStyle(label, radius)
+	if len(opts.Colors) > 0 {
+		style.Color = opts.Colors[label%len(opts.Colors)]
+	}
+	if len(opts.Markers) > 0 {
+		style.Shape = opts.Markers[label%len(opts.Markers)]
+	}
+	return style
+}
+
+// centroidStyle returns the style of the centroid of the given class: a box
+// in the color of the class, three times the radius of its points.
+func (opts PlotOptions) centroidStyle(label int) draw.GlyphStyle {
+	style := opts.glyphStyle(label)
+	style.Shape = draw.BoxGlyph{}
+	style.Radius *= 3
+	return style
+}
+
+// PlotLDAWithOptions plots a 2D LDA transformation on an (X,Y) plane like
+// PlotLDA, with the colors, markers, point size, axis labels and class
+// centroids given by opts, and saves the graph to path in the format given
+// by its extension.
+//
+// Parameter data is the n×2 matrix of transformed data.
+// Parameter labels holds the class of each row of data.
+func PlotLDAWithOptions(data *mat.Dense, labels []int, path, title string, opts PlotOptions) error {
+	pts, err := matrixToPoints(data)
+	if err != nil {
+		return err
+	}
+	if len(labels) != pts.Len() {
+		return fmt.Errorf("The sizes of data and labels don't match")
+	}
+	var centroids plotter.XYs
+	if opts.Centroids != nil {
+		if _, c := opts.Centroids.Dims(); c != 2 {
+			return fmt.Errorf("Centroids must have 2 columns (2D matrix only)")
+		}
+		if centroids, err = matrixToPoints(opts.Centroids); err != nil {
+			return err
+		}
+	}
+	p, err := classScatterPlot(pts, func(i int) draw.GlyphStyle {
+		return opts.glyphStyle(labels[i])
+	})
+	if err != nil {
+		return err
+	}
+	if centroids != nil {
+		sc, err := plotter.NewScatter(centroids)
+		if err != nil {
+			return err
+		}
+		sc.GlyphStyleFunc = opts.centroidStyle
+		p.Add(sc)
+	}
+	p.Title.Text = title
+	if opts.XLabel != "" {
+		p.X.Label.Text = opts.XLabel
+	}
+	if opts.YLabel != "" {
+		p.Y.Label.Text = opts.YLabel
+	}
+	return p.Save(8*vg.Inch, 5*vg.Inch, path)
+}
+
+// PlotLDA3D plots a 3D LDA transformation as an isometric view, so the three
+// discriminant axes appear 120° apart on the page, and saves the graph to
+// path in the format given by its extension.
+//
+// Parameter data is the n×3 matrix of transformed data.
+// Parameter labels holds the class of each row of data.
+func PlotLDA3D(data *mat.Dense, labels []int, path, title string) error {
+	r, c := data.Dims()
+	if c != 3 {
+		return fmt.Errorf("Matrix must have 3 columns (3D matrix only)")
+	}
+	if len(labels) != r {
+		return fmt.Errorf("The sizes of data and labels don't match")
+	}
+
+	// Isometric projection of the X, Y and Z axes onto the page
+	cos, sin := math.Cos(math.Pi/6), math.Sin(math.Pi/6)
+	pts := make(plotter.XYs, r)
+	for i := 0; i < r; i++ {
+		x, y, z := data.At(i, 0), data.At(i, 1), data.At(i, 2)
+		pts[i].X = (x - y) * cos
+		pts[i].Y = z - (x+y)*sin
+	}
+	p, err := classScatterPlot(pts, func(i int) draw.GlyphStyle {
+		return classGlyphStyle(labels[i], vg.Points(3))
+	})
+	if err != nil {
+		return err
+	}
+	p.Title.Text = title
+	return p.Save(8*vg.Inch, 8*vg.Inch, path)
+}
+
+// PlotWithConfidence plots a 2D LDA transformation on an (X,Y) plane and
+// writes the graph to out as a PNG. The radius of each point grows with
+// its prediction confidence, so uncertain points are drawn small.
+//
+// Parameter coords is the n×2 matrix of transformed data.
+// Parameter labels holds the class of each row of coords.
+// Parameter confidences holds the confidence in [0,1] of each row of coords,
+// such as the largest probability returned by PredictProba.
+func PlotWithConfidence(coords *mat.Dense, labels []int, confidences []float64, out io.Writer) error {
+	pts, err := matrixToPoints(coords)
+	if err != nil {
+		return err
+	}
+	r := pts.Len()
+	if len(labels) != r || len(confidences) != r {
+		return fmt.Errorf("The sizes of coords, labels and confidences don't match")
+	}
+	for i, conf := range confidences {
+		if conf < 0 || conf > 1 {
+			return fmt.Errorf("Confidence %v of row %d is outside [0,1]", conf, i)
+		}
+	}
+
+	p, err := classScatterPlot(pts, confidenceGlyphStyles(labels, confidences))
+	if err != nil {
+		return err
+	}
+
+	w, err := p.WriterTo(8*vg.Inch, 5*vg.Inch, "png")
+	if err != nil {
+		return err
+	}
+	_, err = w.WriteTo(out)
+	return err
+}
+
+// ConfidenceEllipse computes the ellipse that contains the given proportion
+// of a class's distribution in the 2D LDA projection, assuming the projected
+// class is normally distributed. The ellipse can be drawn over the scatter
+// plot of the projection.
+//
+// Parameter x is the data to project, and y holds the class of each row.
+// Parameter class is the class whose ellipse is computed.
+// Parameter confidence is the proportion of the distribution in (0,1) that
+// the ellipse contains.
+// Returns the center of the ellipse, the lengths of its semi-axes, and the
+// angle in radians between the X axis and the major axis.
+func (ld *LD) ConfidenceEllipse(x mat.Matrix, y []int, class int, confidence float64) (centerX, centerY float64, semiMajor, semiMinor, angle float64, err error) {
+	if confidence <= 0 || confidence >= 1 {
+		return 0, 0, 0, 0, 0, fmt.Errorf("Confidence %v is outside (0,1)", confidence)
+	}
+	if class < 0 || class >= ld.k {
+		return 0, 0, 0, 0, 0, fmt.Errorf("Invalid class label %d", class)
+	}
+	r, _ := x.Dims()
+	if len(y) != r {
+		return 0, 0, 0, 0, 0, fmt.Errorf("The sizes of X and Y don't match")
+	}
+	projected, err := ld.Transform(x, 2)
+	if err != nil {
+		return 0, 0, 0, 0, 0, err
+	}
+
+	var n float64
+	for i := 0; i < r; i++ {
+		if y[i] == class {
+			centerX += projected.At(i, 0)
+			centerY += projected.At(i, 1)
+			n++
+		}
+	}
+	if n < 2 {
+		return 0, 0, 0, 0, 0, fmt.Errorf("Class %d has fewer than 2 samples", class)
+	}
+	centerX /= n
+	centerY /= n
+
+	// Covariance matrix [a b; b c] of the projected class
+	var a, b, c float64
+	for i := 0; i < r; i++ {
+		if y[i] == class {
+			dx := projected.At(i, 0) - centerX
+			dy := projected.At(i, 1) - centerY
+			a += dx * dx
+			b += dx * dy
+			c += dy * dy
+		}
+	}
+	a /= n - 1
+	b /= n - 1
+	c /= n - 1
+
+	// Eigenvalues of the covariance matrix are the variances along the axes.
+	// The squared Mahalanobis radius of the ellipse is the chi-squared
+	// quantile with 2 degrees of freedom, -2 ln(1-confidence).
+	mid := (a + c) / 2
+	diff := math.Hypot((a-c)/2, b)
+	scale := -2 * math.Log(1-confidence)
+	semiMajor = math.Sqrt(scale * (mid + diff))
+	semiMinor = math.Sqrt(scale * math.Max(mid-diff, 0))
+	angle = math.Atan2(2*b, a-c) / 2
+	return centerX, centerY, semiMajor, semiMinor, angle, nil
+}
+
+// DecisionGrid evaluates Predict on a regular grid over the feature plane of
+// a model with two features, for drawing its decision regions as a contour
+// or heat map under a scatter plot.
+//
+// Parameters xMin, xMax, yMin and yMax bound the grid, including the edges.
+// Parameter steps is the number of grid points along each axis, at least 2.
+// Returns a steps×steps matrix whose entry [i][j] is the class predicted at
+// x = xMin + j*(xMax-xMin)/(steps-1) and y = yMin + i*(yMax-yMin)/(steps-1).
+func (ld *LD) DecisionGrid(xMin, xMax, yMin, yMax float64, steps int) ([][]int, error) {
+	if !ld.ok {
+		return nil, fmt.Errorf("Model has not been fit")
+	}
+	if ld.p != 2 {
+		return nil, fmt.Errorf("Model has %d features, need 2", ld.p)
+	}
+	if steps < 2 {
+		return nil, fmt.Errorf("Invalid number of steps %d", steps)
+	}
+	if !(xMin < xMax) || !(yMin < yMax) {
+		return nil, fmt.Errorf("Invalid grid bounds")
+	}
+	dx := (xMax - xMin) / float64(steps-1)
+	dy := (yMax - yMin) / float64(steps-1)
+	grid := make([][]int, steps)
+	point := make([]float64, 2)
+	for i := range grid {
+		grid[i] = make([]int, steps)
+		point[1] = yMin + float64(i)*dy
+		for j := range grid[i] {
+			point[0] = xMin + float64(j)*dx
+			c, err := ld.Predict(point)
+			if err != nil {
+				return nil, err
+			}
+			grid[i][j] = c
+		}
+	}
+	return grid, nil
+}
+
+// confidenceGlyphStyles returns a glyph style function whose radius scales
+// linearly with the confidence of each point.
+func confidenceGlyphStyles(labels []int, confidences []float64) func(int) draw.GlyphStyle {
+	return func(i int) draw.GlyphStyle {
+		radius := minConfidenceRadius + confidences[i]*(maxConfidenceRadius-minConfidenceRadius)
+		return classGlyphStyle(labels[i], vg.Points(radius))
+	}
+}
+
+// classGlyphStyle returns the color and marker used to draw points of the
+// given class.
+func classGlyphStyle(label int, radius vg.Length) draw.GlyphStyle {
+	r := (map[bool]uint8{true: 128, false: 0})[label&(1<<2) != 0]
+	g := (map[bool]uint8{true: 128, false: 0})[label&(1<<1) != 0]
+	b := (map[bool]uint8{true: 128, false: 0})[label&1 != 0]
+	a := uint8(255)
+	color := color.RGBA{r, g, b, a}
+	markers := [7]draw.GlyphDrawer{
+		draw.CrossGlyph{},
+		draw.CircleGlyph{},
+		draw.PyramidGlyph{},
+		draw.TriangleGlyph{},
+		draw.SquareGlyph{},
+		draw.RingGlyph{},
+		draw.PlusGlyph{},
+	}
+	return draw.GlyphStyle{Color: color, Radius: radius, Shape: markers[label%7]}
+}
+
+// classScatterPlot returns a plot with a grid and a scatter of pts, each point
+// drawn in the glyph style returned by style for its index.
+func classScatterPlot(pts plotter.XYer, style func(int) draw.GlyphStyle) (*plot.Plot, error) {
+	p := plot.New()
+	p.X.Label.Text = "X"
+	p.Y.Label.Text = "Y"
+
+	sc, err := plotter.NewScatter(pts)
+	if err != nil {
+		return nil, err
+	}
+	sc.GlyphStyleFunc = style
+	p.Add(sc)
+	p.Add(plotter.NewGrid())
+	return p, nil
+}
+
+// matrixToPoints returns the rows of the n×2 matrix data as points.
+func matrixToPoints(data *mat.Dense) (plotter.XYs, error) {
+	r, c := data.Dims()
+	if c != 2 {
+		return nil, fmt.Errorf("Matrix must have 2 columns (2D matrix only)")
+	}
+	pts := make(plotter.XYs, r)
+	for i := 0; i < r; i++ {
+		pts[i].X = data.At(i, 0)
+		pts[i].Y = data.At(i, 1)
+	}
+	return pts, nil
+}
@@ -0,0 +1,269 @@
+package lda
+
+import (
+	"bytes"
+	"image/color"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+func TestPlotWithConfidence(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	coords, err := ld.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _ := dataMatrix.Dims()
+	confidences := make([]float64, r)
+	for i := 0; i < r; i++ {
+		prob, err := ld.PredictProba(dataMatrix.RawRowView(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range prob {
+			if v > confidences[i] {
+				confidences[i] = v
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := PlotWithConfidence(coords, labels, confidences, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("\x89PNG")) {
+		t.Error("expected PNG output")
+	}
+	if err := PlotWithConfidence(coords, labels[:10], confidences, &buf); err == nil {
+		t.Error("expected an error for mismatched labels")
+	}
+
+	style := confidenceGlyphStyles([]int{0, 0}, []float64{0.2, 0.9})
+	if style(0).Radius >= style(1).Radius {
+		t.Errorf("expected higher confidence to have a larger radius got:%v and %v", style(0).Radius, style(1).Radius)
+	}
+}
+
+func TestConfidenceEllipse(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	projected, err := ld.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const class = 1
+	var wantX, wantY, n float64
+	for i, label := range labels {
+		if label == class {
+			wantX += projected.At(i, 0)
+			wantY += projected.At(i, 1)
+			n++
+		}
+	}
+	wantX /= n
+	wantY /= n
+
+	var prevMajor, prevMinor float64
+	for _, confidence := range []float64{0.5, 0.9, 0.99} {
+		cx, cy, major, minor, _, err := ld.ConfidenceEllipse(dataMatrix, labels, class, confidence)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(cx-wantX) > 1e-12 || math.Abs(cy-wantY) > 1e-12 {
+			t.Errorf("unexpected center got:(%v, %v), want:(%v, %v)", cx, cy, wantX, wantY)
+		}
+		if minor > major {
+			t.Errorf("semi-minor axis %v exceeds semi-major axis %v", minor, major)
+		}
+		if major <= prevMajor || minor <= prevMinor {
+			t.Errorf("ellipse didn't grow with confidence %v", confidence)
+		}
+		prevMajor, prevMinor = major, minor
+	}
+
+	for _, confidence := range []float64{0, 1} {
+		if _, _, _, _, _, err := ld.ConfidenceEllipse(dataMatrix, labels, class, confidence); err == nil {
+			t.Errorf("expected an error for confidence %v", confidence)
+		}
+	}
+}
+
+func TestPlotLDA3D(t *testing.T) {
+	// Iris has three classes and so only two discriminants; four classes
+	// give three.
+	x, y, _ := syntheticData(200, 4, 4)
+	var ld LD
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	coords, err := ld.Transform(x, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "lda3d.png")
+	if err := PlotLDA3D(coords, y, path, "LDA: 3D"); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if info.Size() == 0 {
+		t.Error("expected a non-empty plot")
+	}
+
+	if err := PlotLDA3D(coords.Slice(0, 200, 0, 2).(*mat.Dense), y, path, "LDA: 2D"); err == nil {
+		t.Error("expected an error for a 2-column matrix")
+	}
+	if err := PlotLDA3D(coords, y[:10], path, "LDA: 3D"); err == nil {
+		t.Error("expected an error for mismatched labels")
+	}
+}
+
+func TestDecisionGrid(t *testing.T) {
+	// Class 0 on the left, class 1 on the right, so the boundary is close
+	// to the vertical line x = 5
+	rnd := rand.New(rand.NewSource(1))
+	x, y := gaussianClasses(rnd, [][]float64{{0, 5}, {10, 5}}, []int{50, 50})
+	var ld LD
+	if _, err := ld.DecisionGrid(-5, 15, -5, 15, 10); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ld.DecisionGrid(-5, 15, -5, 15, 1); err == nil {
+		t.Error("expected an error for too few steps")
+	}
+	if _, err := ld.DecisionGrid(15, -5, -5, 15, 10); err == nil {
+		t.Error("expected an error for inverted bounds")
+	}
+	const steps = 21
+	grid, err := ld.DecisionGrid(-5, 15, 0, 10, steps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(grid) != steps || len(grid[0]) != steps {
+		t.Fatalf("unexpected grid size %d×%d", len(grid), len(grid[0]))
+	}
+	last := steps - 1
+	for _, corner := range []struct{ i, j, want int }{
+		{0, 0, 0},
+		{last, 0, 0},
+		{0, last, 1},
+		{last, last, 1},
+	} {
+		if got := grid[corner.i][corner.j]; got != corner.want {
+			t.Errorf("unexpected class at corner (%d,%d) got:%d, want:%d", corner.i, corner.j, got, corner.want)
+		}
+	}
+
+	var wide LD
+	x3, y3, _ := syntheticData(60, 3, 2)
+	if err := wide.LinearDiscriminant(x3, y3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wide.DecisionGrid(-5, 15, -5, 15, 10); err == nil {
+		t.Error("expected an error for a model with 3 features")
+	}
+}
+
+func TestPlotLDA(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	coords, err := ld.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "lda.png")
+	if err := PlotLDA(coords, labels, path, "LDA: Iris Dataset"); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if info.Size() == 0 {
+		t.Error("expected a non-empty plot")
+	}
+	if err := PlotLDA(dataMatrix, labels, path, ""); err == nil {
+		t.Error("expected an error for a 4-column matrix")
+	}
+	if err := PlotLDA(coords, labels[:10], path, ""); err == nil {
+		t.Error("expected an error for mismatched labels")
+	}
+	if err := PlotLDA(coords, labels, filepath.Join(t.TempDir(), "missing", "lda.png"), ""); err == nil {
+		t.Error("expected an error saving to a missing directory")
+	}
+}
+
+func TestPlotLDAWithOptions(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	coords, err := ld.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := PlotOptions{
+		Colors: []color.Color{
+			color.RGBA{R: 230, G: 25, B: 75, A: 255},
+			color.RGBA{R: 60, G: 180, B: 75, A: 255},
+			color.RGBA{R: 0, G: 130, B: 200, A: 255},
+		},
+		Markers: []draw.GlyphDrawer{draw.CircleGlyph{}, draw.SquareGlyph{}, draw.TriangleGlyph{}},
+		Radius:  vg.Points(4),
+		XLabel:  "LD1",
+		YLabel:  "LD2",
+	}
+	path := filepath.Join(t.TempDir(), "lda.png")
+	if err := PlotLDAWithOptions(coords, labels, path, "LDA: Iris Dataset", opts); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if info.Size() == 0 {
+		t.Error("expected a non-empty plot")
+	}
+	if err := PlotLDAWithOptions(coords, labels[:10], path, "", opts); err == nil {
+		t.Error("expected an error for mismatched labels")
+	}
+
+	if opts.Centroids, err = ld.ProjectedClassMeans(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := PlotLDAWithOptions(coords, labels, path, "LDA: Iris Dataset", opts); err != nil {
+		t.Fatal(err)
+	}
+	centroid := opts.centroidStyle(1)
+	if centroid.Color != opts.Colors[1] || centroid.Shape != (draw.BoxGlyph{}) || centroid.Radius != vg.Points(12) {
+		t.Errorf("unexpected centroid style for class 1 got:%+v", centroid)
+	}
+	opts.Centroids = mat.NewDense(3, 1, nil)
+	if err := PlotLDAWithOptions(coords, labels, path, "", opts); err == nil {
+		t.Error("expected an error for centroids that aren't 2D")
+	}
+
+	style := opts.glyphStyle(4)
+	if style.Color != opts.Colors[1] || style.Shape != opts.Markers[1] || style.Radius != vg.Points(4) {
+		t.Errorf("unexpected style for class 4 got:%+v", style)
+	}
+	if got, want := (PlotOptions{}).glyphStyle(2), classGlyphStyle(2, vg.Points(3)); got != want {
+		t.Errorf("unexpected default style got:%+v, want:%+v", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+package lda
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// EstimatePriorsEM estimates the class proportions of a pool of unlabeled
+// observations with the expectation-maximization procedure of Saerens et al.
+// The class-conditional densities of the fitted model are kept fixed and
+// only the priors are re-estimated. The densities are normal with the class
+// means and the pooled within-class covariance Cw/(n-k), with the shrinkage,
+// ridge and diagonal covariance settings applied as in the fit. The result
+// can be passed to SetPriors to adapt the model to the pool.
+//
+// Parameter unlabeled is a matrix of observations with the same columns as
+// the training data.
+// Parameter iterations is the maximum number of EM iterations. Iteration
+// stops early once the estimate no longer changes.
+// Returns the estimated priori probability of each class.
+func (ld *LD) EstimatePriorsEM(unlabeled mat.Matrix, iterations int) ([]float64, error) {
+	if err := ld.checkClassifier(); err != nil {
+		return nil, err
+	}
+	r, c := unlabeled.Dims()
+	if c != ld.p {
+		return nil, fmt.Errorf("Input has %d features, model trained on %d", c, ld.p)
+	}
+	if r == 0 {
+		return nil, fmt.Errorf("No data to analyze")
+	}
+	if iterations < 1 {
+		return nil, fmt.Errorf("Invalid number of iterations")
+	}
+	unlabeled = ld.standardizeMatrix(unlabeled)
+
+	// The class-conditional densities are normal with the class means and
+	// the pooled within-class covariance.
+	chol, err := ld.pooledCholesky()
+	if err != nil {
+		return nil, err
+	}
+
+	// loglik holds the log class-conditional density of each observation,
+	// up to a constant shared by all classes.
+	loglik := make([][]float64, r)
+	d := mat.NewVecDense(c, nil)
+	var z mat.VecDense
+	for i := 0; i < r; i++ {
+		loglik[i] = make([]float64, ld.k)
+		for j := 0; j < ld.k; j++ {
+			for l := 0; l < c; l++ {
+				d.SetVec(l, unlabeled.At(i, l)-ld.mu.At(j, l))
+			}
+			if err := chol.SolveVecTo(&z, d); err != nil {
+				return nil, err
+			}
+			loglik[i][j] = -0.5 * mat.Dot(d, &z)
+		}
+	}
+
+	priors := make([]float64, ld.k)
+	for j := range priors {
+		priors[j] = math.Exp(ld.ct[j])
+	}
+	posterior := make([]float64, ld.k)
+	next := make([]float64, ld.k)
+	for it := 0; it < iterations; it++ {
+		for j := range next {
+			next[j] = 0
+		}
+		// E-step: posterior of each class for each observation
+		for i := 0; i < r; i++ {
+			max := math.Inf(-1)
+			for j := range posterior {
+				posterior[j] = math.Log(priors[j]) + loglik[i][j]
+				max = math.Max(max, posterior[j])
+			}
+			var sum float64
+			for j := range posterior {
+				posterior[j] = math.Exp(posterior[j] - max)
+				sum += posterior[j]
+			}
+			for j := range posterior {
+				next[j] += posterior[j] / sum
+			}
+		}
+		// M-step: priors are the average posteriors
+		var change float64
+		for j := range priors {
+			next[j] /= float64(r)
+			change = math.Max(change, math.Abs(next[j]-priors[j]))
+			priors[j] = next[j]
+		}
+		if change < 1e-12 {
+			break
+		}
+	}
+	return priors, nil
+}
@@ -0,0 +1,82 @@
+package lda
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// gaussianClasses returns n[c] observations of each class c drawn from unit
+// variance normal distributions centered at the given means.
+func gaussianClasses(rnd *rand.Rand, means [][]float64, n []int) (*mat.Dense, []int) {
+	var total int
+	for _, m := range n {
+		total += m
+	}
+	x := mat.NewDense(total, len(means[0]), nil)
+	y := make([]int, 0, total)
+	row := 0
+	for c, m := range n {
+		for i := 0; i < m; i++ {
+			for j, mu := range means[c] {
+				x.Set(row, j, mu+rnd.NormFloat64())
+			}
+			y = append(y, c)
+			row++
+		}
+	}
+	return x, y
+}
+
+func TestEstimatePriorsEM(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	means := [][]float64{{0, 0}, {5, 5}}
+	x, y := gaussianClasses(rnd, means, []int{100, 100})
+	var ld LD
+	if _, err := ld.EstimatePriorsEM(x, 10); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ld.EstimatePriorsEM(mat.NewDense(2, 3, nil), 10); err == nil {
+		t.Error("expected an error for a column count mismatch")
+	}
+
+	unlabeled, _ := gaussianClasses(rnd, means, []int{90, 210})
+	priors, err := ld.EstimatePriorsEM(unlabeled, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{0.3, 0.7}
+	for i := range want {
+		if math.Abs(priors[i]-want[i]) > 0.05 {
+			t.Errorf("unexpected prior %d got:%v, want:%v", i, priors[i], want[i])
+		}
+	}
+	if err := ld.SetPriors(priors); err != nil {
+		t.Errorf("estimated priors rejected by SetPriors: %v", err)
+	}
+
+	// The densities use the regularized covariance of the fit. A ridge much
+	// larger than the class separation makes them nearly equal, so the
+	// estimate stays close to the training proportions.
+	var ridge LD
+	if err := ridge.SetRidge(1e4); err != nil {
+		t.Fatal(err)
+	}
+	if err := ridge.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	priors, err = ridge.EstimatePriorsEM(unlabeled, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range priors {
+		if math.Abs(priors[i]-0.5) > 0.05 {
+			t.Errorf("unexpected prior %d with a ridge got:%v, want:about 0.5", i, priors[i])
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package lda
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// QD holds the results of a quadratic discriminant analysis. Unlike LD,
+// which assumes that all classes share one covariance matrix, QD estimates
+// a separate covariance matrix for each class, so it can separate classes
+// that differ in their spread rather than only in their means.
+type QD struct {
+	n, p int             // n = # of rows, p = # of columns
+	k    int             // number of classes
+	ct   []float64       // Constant term of discriminant function of each class
+	mu   *mat.Dense      // Mean vectors of each class
+	chol []*mat.Cholesky // Cholesky factorization of the covariance matrix of each class
+}
+
+// Fit performs quadratic discriminant analysis on the matrix of the input
+// data, which is represented as an n×p matrix x, where each row is an
+// observation and each column is a variable.
+//
+// Parameter x is a matrix of input/training data.
+// Parameter y is an array of input/training labels in [0,k), with the same
+// requirements as for LinearDiscriminant. Each class needs more than p
+// samples for its covariance matrix to be invertible.
+func (qd *QD) Fit(x mat.Matrix, y []int) error {
+	n, p := x.Dims()
+	k, err := classLabels(y, n)
+	if err != nil {
+		return err
+	}
+	if k < 2 {
+		return fmt.Errorf("Only one class")
+	}
+	mu, ni, err := classMeans(x, y, k)
+	if err != nil {
+		return err
+	}
+
+	// Scatter matrix of each class
+	scatter := make([]*mat.SymDense, k)
+	for i := range scatter {
+		scatter[i] = mat.NewSymDense(p, nil)
+	}
+	for i := 0; i < n; i++ {
+		c := scatter[y[i]]
+		for j := 0; j < p; j++ {
+			for l := 0; l <= j; l++ {
+				c.SetSym(j, l, c.At(j, l)+(x.At(i, j)-mu.At(y[i], j))*(x.At(i, l)-mu.At(y[i], l)))
+			}
+		}
+	}
+
+	// The discriminant function of class i is
+	// log(prior) - 0.5 log|Σi| - 0.5 (x-μi)'Σi⁻¹(x-μi)
+	tol := defaultTol * defaultTol
+	ct := make([]float64, k)
+	chol := make([]*mat.Cholesky, k)
+	for i := 0; i < k; i++ {
+		if ni[i] <= p {
+			return fmt.Errorf("Class %d has %d samples, need more than %d", i, ni[i], p)
+		}
+		cov := mat.NewSymDense(p, nil)
+		cov.ScaleSym(1/float64(ni[i]-1), scatter[i])
+		for j := 0; j < p; j++ {
+			if cov.At(j, j) < tol {
+				return fmt.Errorf("Covariance matrix of class %d (column %d) is close to singular", i, j)
+			}
+		}
+		chol[i] = &mat.Cholesky{}
+		if ok := chol[i].Factorize(cov); !ok {
+			return fmt.Errorf("Covariance matrix of class %d is not positive definite", i)
+		}
+		ct[i] = math.Log(float64(ni[i])/float64(n)) - 0.5*chol[i].LogDet()
+	}
+
+	qd.n, qd.p, qd.k = n, p, k
+	qd.ct, qd.mu, qd.chol = ct, mu, chol
+	return nil
+}
+
+// Predict returns the class whose quadratic discriminant function is largest
+// for the input x.
+//
+// Parameter x is the set of data to classify, of length p.
+func (qd *QD) Predict(x []float64) (int, error) {
+	if qd.mu == nil {
+		return 0, fmt.Errorf("Model has not been fit")
+	}
+	if len(x) != qd.p {
+		return 0, fmt.Errorf("Invalid input vector size")
+	}
+	d := mat.NewVecDense(qd.p, nil)
+	var z mat.VecDense
+	y := 0
+	max := math.Inf(-1)
+	for i := 0; i < qd.k; i++ {
+		for j := 0; j < qd.p; j++ {
+			d.SetVec(j, x[j]-qd.mu.At(i, j))
+		}
+		if err := qd.chol[i].SolveVecTo(&z, d); err != nil {
+			return 0, err
+		}
+		f := qd.ct[i] - 0.5*mat.Dot(d, &z)
+		if max < f {
+			max = f
+			y = i
+		}
+	}
+	return y, nil
+}
@@ -0,0 +1,64 @@
+package lda
+
+import (
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestQD(t *testing.T) {
+	// Two classes with nearby means, the second spread four times as widely
+	rnd := rand.New(rand.NewSource(1))
+	x, y := gaussianClasses(rnd, [][]float64{{0, 0}, {0, 0}}, []int{200, 200})
+	r, c := x.Dims()
+	for i := 0; i < r; i++ {
+		if y[i] == 1 {
+			for j := 0; j < c; j++ {
+				x.Set(i, j, 1+4*x.At(i, j))
+			}
+		}
+	}
+
+	var qd QD
+	if _, err := qd.Predict([]float64{0, 0}); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	if err := qd.Fit(x, y[1:]); err == nil {
+		t.Error("expected an error for mismatched labels")
+	}
+	if err := qd.Fit(mat.NewDense(4, 2, []float64{0, 1, 1, 0, 5, 6, 6, 5}), []int{0, 0, 1, 1}); err == nil {
+		t.Error("expected an error for classes with too few samples")
+	}
+	if err := qd.Fit(x, y); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := qd.Predict([]float64{0}); err == nil {
+		t.Error("expected an error for a short input vector")
+	}
+
+	var ld LD
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	var qdCorrect, ldCorrect int
+	for i := 0; i < r; i++ {
+		row := x.RawRowView(i)
+		if c, err := qd.Predict(row); err != nil {
+			t.Fatal(err)
+		} else if c == y[i] {
+			qdCorrect++
+		}
+		if c, err := ld.Predict(row); err != nil {
+			t.Fatal(err)
+		} else if c == y[i] {
+			ldCorrect++
+		}
+	}
+	if qdCorrect <= ldCorrect {
+		t.Errorf("QD is not more accurate than LD: %d vs %d of %d", qdCorrect, ldCorrect, r)
+	}
+	if float64(qdCorrect)/float64(r) < 0.8 {
+		t.Errorf("unexpected QD accuracy got:%d of %d", qdCorrect, r)
+	}
+}
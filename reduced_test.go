@@ -0,0 +1,72 @@
+package lda
+
+import "testing"
+
+func TestReducedPredict(t *testing.T) {
+	var ld LD
+	if _, err := ld.ReducedPredict([]float64{1, 2, 3, 4}); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	dataMatrix, labels, _ := loadIris(t)
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ld.ReducedPredict([]float64{1, 2}); err == nil {
+		t.Error("expected an error for the wrong number of features")
+	}
+	r, _ := dataMatrix.Dims()
+	for i := 0; i < r; i++ {
+		x := dataMatrix.RawRowView(i)
+		want, err := ld.Predict(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ld.ReducedPredict(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("unexpected prediction for row %d got:%d, want:%d", i, got, want)
+		}
+	}
+
+	// Standardized and wide models agree too
+	x, y, _ := syntheticData(2000, 50, 4)
+	ld.SetStandardize(true)
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 200; i++ {
+		want, _ := ld.Predict(x.RawRowView(i))
+		got, err := ld.ReducedPredict(x.RawRowView(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("unexpected prediction for synthetic row %d got:%d, want:%d", i, got, want)
+		}
+	}
+}
+
+func benchmarkWidePredict(b *testing.B, reduced bool) {
+	x, y, _ := syntheticData(2000, 100, 5)
+	var ld LD
+	if err := ld.LinearDiscriminant(x, y); err != nil {
+		b.Fatal(err)
+	}
+	predict := ld.Predict
+	if reduced {
+		predict = ld.ReducedPredict
+	}
+	row := x.RawRowView(0)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := predict(row); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWidePredict(b *testing.B)        { benchmarkWidePredict(b, false) }
+func BenchmarkWideReducedPredict(b *testing.B) { benchmarkWidePredict(b, true) }
@@ -0,0 +1,93 @@
+package lda
+
+import (
+	"context"
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// cancelCheckRows is the number of rows the scatter loops process between
+// checks for cancellation of their context.
+const cancelCheckRows = 256
+
+// withinScatter computes the within-class scatter matrix of x, the sum over
+// all rows of (x-mu)(x-mu)' where mu is the mean of the row's class. It
+// returns ctx.Err() if ctx is cancelled before the matrix is complete.
+func withinScatter(ctx context.Context, x mat.Matrix, y []int, mu *mat.Dense) (*mat.SymDense, error) {
+	n, p := x.Dims()
+	// Cw is the within-class scatter matrix initialized as a p x p zero matrix
+	Cw := mat.NewSymDense(p, make([]float64, p*p, p*p))
+
+	for i := 0; i < n; i++ {
+		if i%cancelCheckRows == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		for j := 0; j < p; j++ {
+			for l := 0; l <= j; l++ {
+				Cw.SetSym(j, l, (Cw.At(j, l) + ((x.At(i, j) - mu.At(y[i], j)) * (x.At(i, l) - mu.At(y[i], l)))))
+			}
+		}
+	}
+	return Cw, nil
+}
+
+// withinScatterParallel computes the same matrix as withinScatter by
+// splitting the rows of x between the given number of workers. Each worker
+// accumulates the scatter of its rows into a private buffer, and the buffers
+// are summed at the end. Every worker stops early if ctx is cancelled, and
+// ctx.Err() is returned.
+func withinScatterParallel(ctx context.Context, x mat.Matrix, y []int, mu *mat.Dense, workers int) (*mat.SymDense, error) {
+	n, p := x.Dims()
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		return withinScatter(ctx, x, y, mu)
+	}
+
+	buffers := make([][]float64, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			// Lower triangle of the p x p scatter of rows [start,end)
+			buf := make([]float64, p*p)
+			d := make([]float64, p)
+			start, end := w*n/workers, (w+1)*n/workers
+			for i := start; i < end; i++ {
+				if (i-start)%cancelCheckRows == 0 && ctx.Err() != nil {
+					return
+				}
+				for j := 0; j < p; j++ {
+					d[j] = x.At(i, j) - mu.At(y[i], j)
+				}
+				for j := 0; j < p; j++ {
+					for l := 0; l <= j; l++ {
+						buf[j*p+l] += d[j] * d[l]
+					}
+				}
+			}
+			buffers[w] = buf
+		}(w)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	Cw := mat.NewSymDense(p, nil)
+	for j := 0; j < p; j++ {
+		for l := 0; l <= j; l++ {
+			var sum float64
+			for _, buf := range buffers {
+				sum += buf[j*p+l]
+			}
+			Cw.SetSym(j, l, sum)
+		}
+	}
+	return Cw, nil
+}
@@ -0,0 +1,77 @@
+package lda
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// syntheticData returns n rows of p normally distributed features in k
+// classes with shifted means, along with their class means.
+func syntheticData(n, p, k int) (*mat.Dense, []int, *mat.Dense) {
+	rnd := rand.New(rand.NewSource(1))
+	x := mat.NewDense(n, p, nil)
+	y := make([]int, n)
+	for i := 0; i < n; i++ {
+		y[i] = i % k
+		for j := 0; j < p; j++ {
+			x.Set(i, j, float64(y[i]*(j%3))+rnd.NormFloat64())
+		}
+	}
+	mu, _, err := classMeans(x, y, k)
+	if err != nil {
+		panic(err)
+	}
+	return x, y, mu
+}
+
+func TestWithinScatterParallel(t *testing.T) {
+	x, y, mu := syntheticData(1000, 20, 3)
+	serial, err := withinScatter(context.Background(), x, y, mu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, workers := range []int{1, 2, 7, runtime.NumCPU(), 2000} {
+		parallel, err := withinScatterParallel(context.Background(), x, y, mu, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j := 0; j < 20; j++ {
+			for l := 0; l < 20; l++ {
+				want := serial.At(j, l)
+				if got := parallel.At(j, l); math.Abs(got-want) > 1e-9*math.Max(1, math.Abs(want)) {
+					t.Fatalf("unexpected element (%d,%d) with %d workers got:%v, want:%v", j, l, workers, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestWithinScatterCancel(t *testing.T) {
+	x, y, mu := syntheticData(1000, 20, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, workers := range []int{1, 4} {
+		if _, err := withinScatterParallel(ctx, x, y, mu, workers); err != context.Canceled {
+			t.Errorf("unexpected error with %d workers got:%v, want:%v", workers, err, context.Canceled)
+		}
+	}
+}
+
+func BenchmarkWithinScatter(b *testing.B) {
+	x, y, mu := syntheticData(5000, 50, 3)
+	b.Run("serial", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			withinScatter(context.Background(), x, y, mu)
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			withinScatterParallel(context.Background(), x, y, mu, runtime.NumCPU())
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package lda
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// setScoreStatistics computes the mean and standard deviation of each
+// class's discriminant score over the training data x.
+func (ld *LD) setScoreStatistics(x mat.Matrix) error {
+	r, c := x.Dims()
+	scores := make([][]float64, r)
+	row := make([]float64, c)
+	for i := 0; i < r; i++ {
+		mat.Row(row, i, x)
+		var err error
+		scores[i], err = ld.DecisionFunction(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	ld.scoreMean = make([]float64, ld.k)
+	ld.scoreStd = make([]float64, ld.k)
+	for j := 0; j < ld.k; j++ {
+		var sum float64
+		for i := 0; i < r; i++ {
+			sum += scores[i][j]
+		}
+		mean := sum / float64(r)
+		var ss float64
+		for i := 0; i < r; i++ {
+			ss += (scores[i][j] - mean) * (scores[i][j] - mean)
+		}
+		ld.scoreMean[j] = mean
+		ld.scoreStd[j] = math.Sqrt(ss / float64(r))
+	}
+	return nil
+}
+
+// StandardizedScores computes the discriminant score of each class for the
+// input x as a z-score: the score minus the mean of that class's scores over
+// the training data, divided by their standard deviation. Standardized
+// scores are comparable across differently-scaled models.
+//
+// The score statistics are computed by LinearDiscriminant and are not
+// available after the model is updated with AddSample or RemoveSample.
+func (ld *LD) StandardizedScores(x []float64) ([]float64, error) {
+	if ld.scoreMean == nil {
+		return nil, fmt.Errorf("Score statistics are not available")
+	}
+	scores, err := ld.DecisionFunction(x)
+	if err != nil {
+		return nil, err
+	}
+	for j := range scores {
+		scores[j] = (scores[j] - ld.scoreMean[j]) / ld.scoreStd[j]
+	}
+	return scores, nil
+}
@@ -0,0 +1,49 @@
+package lda
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStandardizedScores(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if _, err := ld.StandardizedScores([]float64{5.0, 3.3, 1.4, 0.2}); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+
+	r, _ := dataMatrix.Dims()
+	sum := make([]float64, ld.k)
+	sumSq := make([]float64, ld.k)
+	for i := 0; i < r; i++ {
+		z, err := ld.StandardizedScores(dataMatrix.RawRowView(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j, v := range z {
+			sum[j] += v
+			sumSq[j] += v * v
+		}
+	}
+	const epsilon = 1e-6
+	for j := 0; j < ld.k; j++ {
+		mean := sum[j] / float64(r)
+		variance := sumSq[j]/float64(r) - mean*mean
+		if math.Abs(mean) > epsilon {
+			t.Errorf("unexpected mean of class %d scores: %v", j, mean)
+		}
+		if math.Abs(variance-1) > epsilon {
+			t.Errorf("unexpected variance of class %d scores: %v", j, variance)
+		}
+	}
+
+	if err := ld.RemoveSample(dataMatrix.RawRowView(0), labels[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ld.StandardizedScores(dataMatrix.RawRowView(0)); err == nil {
+		t.Error("expected an error after updating the model")
+	}
+}
@@ -0,0 +1,111 @@
+package lda
+
+import (
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// PairwiseSeparability computes how well each pair of classes can be
+// separated by a single linear direction.
+//
+// Entry (a,b) of the returned k×k matrix is the Fisher ratio of the best
+// direction separating classes a and b, (w'(μa-μb))² / (w'Σw) with
+// w = Σ⁻¹(μa-μb), which equals the squared Mahalanobis distance between the
+// class means under the pooled within-class covariance Σ. Σ is regularized
+// by the shrinkage, ridge and diagonal covariance settings as in the fit.
+// The matrix is symmetric with a zero diagonal; larger entries mean the pair
+// is easier to separate.
+func (ld *LD) PairwiseSeparability() (*mat.Dense, error) {
+	if !ld.ok {
+		return nil, fmt.Errorf("Model has not been fit")
+	}
+	chol, err := ld.pooledCholesky()
+	if err != nil {
+		return nil, err
+	}
+	sep := mat.NewDense(ld.k, ld.k, nil)
+	d := mat.NewVecDense(ld.p, nil)
+	var w mat.VecDense
+	for a := 0; a < ld.k; a++ {
+		for b := a + 1; b < ld.k; b++ {
+			for j := 0; j < ld.p; j++ {
+				d.SetVec(j, ld.mu.At(a, j)-ld.mu.At(b, j))
+			}
+			if err := chol.SolveVecTo(&w, d); err != nil {
+				return nil, err
+			}
+			ratio := mat.Dot(d, &w)
+			sep.Set(a, b, ratio)
+			sep.Set(b, a, ratio)
+		}
+	}
+	return sep, nil
+}
+
+// Separability measures how well the classes are separated overall, as the
+// sum of the eigenvalues of Σ⁻¹Cb, where Σ is the pooled within-class
+// covariance and Cb the between-class scatter matrix. Larger values mean the
+// classes are easier to separate.
+func (ld *LD) Separability() (float64, error) {
+	if !ld.ok {
+		return 0, fmt.Errorf("Model has not been fit")
+	}
+	var sum float64
+	for _, v := range ld.evals {
+		sum += real(v)
+	}
+	return sum, nil
+}
+
+// BestFeatureSubset selects the size features of x that best separate the
+// classes by greedy forward selection. Starting from no features, it adds
+// at each step the feature whose addition gives the largest Separability.
+// Subsets are fit with the settings of ld, which is not modified.
+//
+// Parameter x is the matrix of input data.
+// Parameter y holds the class of each row of x, in [0,k).
+// Parameter size is the number of features to select, in [1,p].
+// Returns the selected column indices of x in increasing order.
+func (ld *LD) BestFeatureSubset(x mat.Matrix, y []int, size int) ([]int, error) {
+	r, p := x.Dims()
+	if size < 1 || size > p {
+		return nil, fmt.Errorf("Invalid subset size %d for %d features", size, p)
+	}
+	var selected []int
+	used := make([]bool, p)
+	for len(selected) < size {
+		best, bestSep := -1, 0.0
+		for j := 0; j < p; j++ {
+			if used[j] {
+				continue
+			}
+			cols := append(append([]int(nil), selected...), j)
+			sub := mat.NewDense(r, len(cols), nil)
+			for i := 0; i < r; i++ {
+				for c, col := range cols {
+					sub.Set(i, c, x.At(i, col))
+				}
+			}
+			trial := ld.settings()
+			if err := trial.LinearDiscriminant(sub, y); err != nil {
+				continue
+			}
+			sep, err := trial.Separability()
+			if err != nil {
+				return nil, err
+			}
+			if best < 0 || sep > bestSep {
+				best, bestSep = j, sep
+			}
+		}
+		if best < 0 {
+			return nil, fmt.Errorf("No feature can be added to %v", selected)
+		}
+		selected = append(selected, best)
+		used[best] = true
+	}
+	sort.Ints(selected)
+	return selected, nil
+}
@@ -0,0 +1,140 @@
+package lda
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPairwiseSeparability(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if _, err := ld.PairwiseSeparability(); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	sep, err := ld.PairwiseSeparability()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, c := sep.Dims(); r != 3 || c != 3 {
+		t.Fatalf("unexpected dimensions got:%d×%d, want:3×3", r, c)
+	}
+	for i := 0; i < 3; i++ {
+		if sep.At(i, i) != 0 {
+			t.Errorf("unexpected diagonal entry %d: %v", i, sep.At(i, i))
+		}
+	}
+
+	// Classes are Versicolor (0), Virginica (1) and Setosa (2)
+	hard := sep.At(0, 1)
+	for _, easy := range []float64{sep.At(2, 0), sep.At(2, 1)} {
+		if easy < 5*hard {
+			t.Errorf("expected Setosa to be much more separable got:%v, Versicolor vs Virginica:%v", easy, hard)
+		}
+	}
+}
+
+func TestPairwiseSeparabilityDiagonal(t *testing.T) {
+	// With a diagonal covariance each feature contributes its own squared
+	// standardized mean difference
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	ld.SetDiagonalCovariance(true)
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	sep, err := ld.PairwiseSeparability()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for a := 0; a < ld.k; a++ {
+		for b := a + 1; b < ld.k; b++ {
+			var want float64
+			for j := 0; j < ld.p; j++ {
+				d := ld.mu.At(a, j) - ld.mu.At(b, j)
+				want += d * d * float64(ld.n-ld.k) / ld.cw.At(j, j)
+			}
+			if got := sep.At(a, b); math.Abs(got-want) > 1e-9*want {
+				t.Errorf("unexpected separability of classes %d and %d got:%v, want:%v", a, b, got, want)
+			}
+		}
+	}
+}
+
+func TestBestFeatureSubset(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if _, err := ld.Separability(); err == nil {
+		t.Error("expected an error for an unfitted model")
+	}
+	for _, size := range []int{0, 5} {
+		if _, err := ld.BestFeatureSubset(dataMatrix, labels, size); err == nil {
+			t.Errorf("expected an error for subset size %d", size)
+		}
+	}
+
+	// Petal length separates the species best on its own. Petal width is
+	// strongly correlated with it within each species, so sepal length adds
+	// more separability as the second feature.
+	for size, want := range map[int][]int{1: {2}, 2: {0, 2}} {
+		got, err := ld.BestFeatureSubset(dataMatrix, labels, size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(want) {
+			t.Errorf("unexpected feature subset got:%v, want:%v", got, want)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("unexpected feature subset got:%v, want:%v", got, want)
+				break
+			}
+		}
+	}
+	if ld.mu != nil {
+		t.Error("BestFeatureSubset should not fit the receiver")
+	}
+}
+
+func TestBestFeatureSubsetSettings(t *testing.T) {
+	// The first feature separates the classes best on its own scale, but a
+	// ridge much larger than its variance hides it behind the second
+	x := mat.NewDense(12, 2, []float64{
+		0.010, -1,
+		0.011, 1,
+		0.009, 0,
+		0.010, 1.5,
+		0.011, -1.5,
+		0.009, 0,
+		0.020, 2,
+		0.021, 4,
+		0.019, 3,
+		0.020, 4.5,
+		0.021, 1.5,
+		0.019, 3,
+	})
+	y := []int{0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 1, 1}
+	var ld LD
+	got, err := ld.BestFeatureSubset(x, y, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != 0 {
+		t.Errorf("unexpected subset without a ridge got:%v, want:[0]", got)
+	}
+	if err := ld.SetRidge(1); err != nil {
+		t.Fatal(err)
+	}
+	got, err = ld.BestFeatureSubset(x, y, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != 1 {
+		t.Errorf("unexpected subset with a ridge got:%v, want:[1]", got)
+	}
+}
@@ -0,0 +1,32 @@
+package lda
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// TransformSlice performs the same transformation as Transform, but returns
+// the projected coordinates as one slice per row, for callers that don't use
+// gonum matrices.
+//
+// Parameter x is the matrix to be transformed.
+// Parameter n is the number of dimensions desired, as for Transform.
+func (ld *LD) TransformSlice(x mat.Matrix, n int) ([][]float64, error) {
+	result, err := ld.Transform(x, n)
+	if err != nil {
+		return nil, err
+	}
+	return DenseToSlice(result), nil
+}
+
+// DenseToSlice copies the rows of m into a slice of slices. The rows share
+// one backing array but not the memory of m.
+func DenseToSlice(m *mat.Dense) [][]float64 {
+	r, c := m.Dims()
+	data := make([]float64, r*c)
+	rows := make([][]float64, r)
+	for i := range rows {
+		rows[i] = data[i*c : (i+1)*c : (i+1)*c]
+		mat.Row(rows[i], i, m)
+	}
+	return rows
+}
@@ -0,0 +1,49 @@
+package lda
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestTransformSlice(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ld.TransformSlice(dataMatrix, 3); err == nil {
+		t.Error("expected an error for too many dimensions")
+	}
+	want, err := ld.Transform(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ld.TransformSlice(dataMatrix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, c := want.Dims()
+	if len(got) != r {
+		t.Fatalf("unexpected number of rows got:%d, want:%d", len(got), r)
+	}
+	for i := 0; i < r; i++ {
+		if len(got[i]) != c {
+			t.Fatalf("unexpected length of row %d got:%d, want:%d", i, len(got[i]), c)
+		}
+		for j := 0; j < c; j++ {
+			if got[i][j] != want.At(i, j) {
+				t.Errorf("unexpected element (%d,%d) got:%v, want:%v", i, j, got[i][j], want.At(i, j))
+			}
+		}
+	}
+
+	// The rows don't alias the matrix or each other
+	m := mat.NewDense(2, 2, []float64{1, 2, 3, 4})
+	rows := DenseToSlice(m)
+	rows[0] = append(rows[0], 5)
+	rows[0][0] = 0
+	if m.At(0, 0) != 1 || rows[1][0] != 3 {
+		t.Error("DenseToSlice rows alias other memory")
+	}
+}
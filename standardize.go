@@ -0,0 +1,93 @@
+package lda
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// SetStandardize sets whether LinearDiscriminant z-scores each feature
+// before fitting, subtracting its mean and dividing by its standard
+// deviation. The means and standard deviations of the training data are
+// stored, and the same transformation is applied to the data passed to
+// Predict, Transform and the other methods of the fitted model. This keeps
+// features measured on very different scales from distorting the covariance
+// estimate. The default is not to standardize.
+func (ld *LD) SetStandardize(standardize bool) {
+	ld.standardize = standardize
+}
+
+// standardizeFit computes the mean and standard deviation of each column of
+// x, stores them, and returns the standardized copy of x. Columns whose
+// standard deviation is less than tol are rejected.
+func (ld *LD) standardizeFit(x mat.Matrix) (*mat.Dense, error) {
+	r, c := x.Dims()
+	if r < 2 {
+		return nil, fmt.Errorf("Sample size is too small")
+	}
+	center := make([]float64, c)
+	scale := make([]float64, c)
+	col := make([]float64, r)
+	for j := 0; j < c; j++ {
+		mat.Col(col, j, x)
+		var sum float64
+		for _, v := range col {
+			sum += v
+		}
+		mean := sum / float64(r)
+		var ss float64
+		for _, v := range col {
+			ss += (v - mean) * (v - mean)
+		}
+		std := math.Sqrt(ss / float64(r-1))
+		if std < ld.tolerance() {
+			return nil, fmt.Errorf("Standard deviation of column %d is close to zero", j)
+		}
+		center[j], scale[j] = mean, std
+	}
+	ld.center, ld.scale = center, scale
+	return ld.standardizeMatrix(x).(*mat.Dense), nil
+}
+
+// standardizeRow returns x standardized with the stored column means and
+// standard deviations, or x itself if the model doesn't standardize.
+func (ld *LD) standardizeRow(x []float64) []float64 {
+	if ld.scale == nil || len(x) != len(ld.scale) {
+		return x
+	}
+	z := make([]float64, len(x))
+	for j, v := range x {
+		z[j] = (v - ld.center[j]) / ld.scale[j]
+	}
+	return z
+}
+
+// standardizeMatrix returns the rows of x standardized like standardizeRow,
+// or x itself if the model doesn't standardize.
+func (ld *LD) standardizeMatrix(x mat.Matrix) mat.Matrix {
+	r, c := x.Dims()
+	if ld.scale == nil || c != len(ld.scale) {
+		return x
+	}
+	z := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			z.Set(i, j, (x.At(i, j)-ld.center[j])/ld.scale[j])
+		}
+	}
+	return z
+}
+
+// unstandardizeMatrix undoes standardizeMatrix in place.
+func (ld *LD) unstandardizeMatrix(z *mat.Dense) {
+	if ld.scale == nil {
+		return
+	}
+	r, c := z.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			z.Set(i, j, z.At(i, j)*ld.scale[j]+ld.center[j])
+		}
+	}
+}
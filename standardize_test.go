@@ -0,0 +1,71 @@
+package lda
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSetStandardize(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	// Sepal length in thousandths
+	scaled := mat.DenseCopyOf(dataMatrix)
+	r, _ := scaled.Dims()
+	for i := 0; i < r; i++ {
+		scaled.Set(i, 0, 1000*scaled.At(i, 0))
+	}
+
+	// Shrinkage towards a multiple of the identity depends on the scale of
+	// the features, so the rescaled column costs accuracy unless the
+	// features are standardized.
+	accuracy := func(standardize bool) float64 {
+		var ld LD
+		if err := ld.SetShrinkage(0.01); err != nil {
+			t.Fatal(err)
+		}
+		ld.SetStandardize(standardize)
+		if err := ld.LinearDiscriminant(scaled, labels); err != nil {
+			t.Fatal(err)
+		}
+		var correct int
+		for i := 0; i < r; i++ {
+			c, err := ld.Predict(scaled.RawRowView(i))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c == labels[i] {
+				correct++
+			}
+		}
+		return float64(correct) / float64(r)
+	}
+	raw, standardized := accuracy(false), accuracy(true)
+	if standardized < 0.95 {
+		t.Errorf("unexpected accuracy with standardization got:%v, want at least 0.95", standardized)
+	}
+	if raw >= standardized {
+		t.Errorf("standardization didn't improve accuracy: %v without, %v with", raw, standardized)
+	}
+
+	// Class means are reported in the original units
+	var ld LD
+	ld.SetStandardize(true)
+	if err := ld.LinearDiscriminant(scaled, labels); err != nil {
+		t.Fatal(err)
+	}
+	// Setosa is class 2
+	if got, want := ld.ClassMeans().At(2, 0), 5006.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("unexpected class mean got:%v, want:%v", got, want)
+	}
+
+	constant := mat.NewDense(4, 2, []float64{
+		1, 3,
+		2, 3,
+		5, 3,
+		6, 3,
+	})
+	if err := ld.LinearDiscriminant(constant, []int{0, 0, 1, 1}); err == nil {
+		t.Error("expected an error for a constant column")
+	}
+}
@@ -0,0 +1,121 @@
+package lda
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// AddSample updates a fitted model with one additional observation without
+// recomputing the statistics of the whole training set. The class mean,
+// the scatter matrices and the class counts are updated, and the eigenvalue
+// problem is solved again.
+//
+// Parameter x is the observation to add.
+// Parameter label is the class of x in [0,k).
+func (ld *LD) AddSample(x []float64, label int) error {
+	return ld.updateSample(x, label, 1)
+}
+
+// RemoveSample updates a fitted model as if the observation x had not been
+// part of the training set, without recomputing the statistics of the whole
+// training set. The class mean, the scatter matrices and the class counts are
+// downdated, and the eigenvalue problem is solved again.
+//
+// Parameter x is the observation to remove. It must have been part of the
+// training data for the result to be meaningful.
+// Parameter label is the class of x in [0,k). The class must have more than
+// two samples, so that at least two remain.
+func (ld *LD) RemoveSample(x []float64, label int) error {
+	return ld.updateSample(x, label, -1)
+}
+
+// updateSample adds (sign = 1) or removes (sign = -1) the observation x of
+// class label from the fitted statistics. The model is left unchanged if the
+// eigenvalue problem can't be solved with the updated statistics.
+func (ld *LD) updateSample(x []float64, label int, sign int) error {
+	if !ld.ok {
+		return fmt.Errorf("Model has not been fit")
+	}
+	if len(x) != ld.p {
+		return fmt.Errorf("Invalid input vector size")
+	}
+	if label < 0 || label >= ld.k {
+		return fmt.Errorf("Invalid class label %d", label)
+	}
+	x = ld.standardizeRow(x)
+	if sign < 0 {
+		// Every class keeps at least 2 samples, as required by a fit
+		if ld.ni[label] <= 2 {
+			return fmt.Errorf("Class %d has %d samples, at least 2 must remain", label, ld.ni[label])
+		}
+		if ld.n-1 <= ld.k {
+			return fmt.Errorf("Sample size is too small")
+		}
+	}
+
+	// The statistics are changed in place, so keep a copy of the model to
+	// restore if the eigenvalue problem can't be solved afterwards
+	saved := *ld
+	saved.mu = mat.DenseCopyOf(ld.mu)
+	saved.cw = mat.NewSymDense(ld.p, nil)
+	saved.cw.CopySym(ld.cw)
+	saved.ni = append([]int(nil), ld.ni...)
+
+	// Scatter of a class of m samples with mean mu changes by
+	// ±m/(m±1) (x-mu)(x-mu)' when x is added or removed.
+	m := float64(ld.ni[label])
+	mNew := m + float64(sign)
+	d := make([]float64, ld.p)
+	for j := 0; j < ld.p; j++ {
+		d[j] = x[j] - ld.mu.At(label, j)
+	}
+	w := float64(sign) * m / mNew
+	for j := 0; j < ld.p; j++ {
+		for l := 0; l <= j; l++ {
+			ld.cw.SetSym(j, l, ld.cw.At(j, l)+w*d[j]*d[l])
+		}
+		ld.mu.Set(label, j, ld.mu.At(label, j)+float64(sign)*d[j]/mNew)
+	}
+	ld.ni[label] += sign
+	ld.n += sign
+
+	if err := ld.refit(); err != nil {
+		*ld = saved
+		return err
+	}
+	return nil
+}
+
+// refit recomputes the between-class scatter matrix and the constant terms
+// from the class counts and means, and solves the eigenvalue problem again.
+// The model counts as unfitted unless it succeeds.
+func (ld *LD) refit() error {
+	ld.ok = false
+
+	// The training data isn't available to recompute the score statistics
+	ld.scoreMean, ld.scoreStd = nil, nil
+
+	// Common mean vector
+	colmean := make([]float64, ld.p)
+	for i := 0; i < ld.k; i++ {
+		for j := 0; j < ld.p; j++ {
+			colmean[j] += float64(ld.ni[i]) * ld.mu.At(i, j) / float64(ld.n)
+		}
+	}
+	if err := ld.checkClassWeights(); err != nil {
+		return err
+	}
+	if !ld.transformOnly {
+		if err := ld.setConstants(); err != nil {
+			return err
+		}
+	}
+	ld.cb = ld.betweenScatter(colmean)
+	ld.mean = colmean
+	if err := ld.solve(); err != nil {
+		return err
+	}
+	ld.ok = true
+	return nil
+}
@@ -0,0 +1,158 @@
+package lda
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestRemoveSample(t *testing.T) {
+	dataMatrix, labels, _ := loadIris(t)
+	var ld LD
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	var orig LD
+	if err := orig.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+	const epsilon = 1e-9
+
+	x := dataMatrix.RawRowView(0)
+	if err := ld.RemoveSample(x[:2], labels[0]); err == nil {
+		t.Error("expected an error for a short input vector")
+	}
+	if err := ld.RemoveSample(x, ld.k); err == nil {
+		t.Error("expected an error for an invalid label")
+	}
+	if err := ld.RemoveSample(x, labels[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Removing the sample matches fitting without it
+	r, c := dataMatrix.Dims()
+	var refit LD
+	if err := refit.LinearDiscriminant(dataMatrix.Slice(1, r, 0, c), labels[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if !mat.EqualApprox(ld.mu, refit.mu, epsilon) {
+		t.Errorf("unexpected class means after removal")
+	}
+	if !mat.EqualApprox(ld.cw, refit.cw, epsilon) {
+		t.Errorf("unexpected within-class scatter after removal")
+	}
+	if !mat.EqualApprox(ld.cb, refit.cb, epsilon) {
+		t.Errorf("unexpected between-class scatter after removal")
+	}
+
+	// Adding it back restores the original model
+	if err := ld.AddSample(x, labels[0]); err != nil {
+		t.Fatal(err)
+	}
+	if ld.n != orig.n || ld.ni[labels[0]] != orig.ni[labels[0]] {
+		t.Errorf("unexpected counts after re-adding got:%d, want:%d", ld.n, orig.n)
+	}
+	if !mat.EqualApprox(ld.mu, orig.mu, epsilon) || !mat.EqualApprox(ld.cw, orig.cw, epsilon) {
+		t.Errorf("statistics not restored after re-adding the sample")
+	}
+	for i := 0; i < 2; i++ {
+		if math.Abs(cmplx.Abs(ld.evals[i])-cmplx.Abs(orig.evals[i])) > 1e-6 {
+			t.Errorf("unexpected eigenvalue %d got:%v, want:%v", i, ld.evals[i], orig.evals[i])
+		}
+	}
+	for i := range ld.ct {
+		if math.Abs(ld.ct[i]-orig.ct[i]) > epsilon {
+			t.Errorf("unexpected constant term %d got:%v, want:%v", i, ld.ct[i], orig.ct[i])
+		}
+	}
+}
+
+func TestRemoveSampleFailure(t *testing.T) {
+	// The second feature only varies within class 0 through its second
+	// sample, so removing it makes the covariance matrix singular
+	data := mat.NewDense(6, 2, []float64{
+		1, 0,
+		2, 1,
+		3, 0,
+		5, 0,
+		6, 0,
+		7, 0,
+	})
+	labels := []int{0, 0, 0, 1, 1, 1}
+	var ld LD
+	if err := ld.LinearDiscriminant(data, labels); err != nil {
+		t.Fatal(err)
+	}
+	var orig LD
+	if err := orig.LinearDiscriminant(data, labels); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ld.RemoveSample(data.RawRowView(1), labels[1]); err == nil {
+		t.Fatal("expected an error for a singular covariance matrix")
+	}
+	if !ld.ok {
+		t.Error("model no longer fitted after a failed removal")
+	}
+	if ld.n != orig.n || ld.ni[0] != orig.ni[0] || ld.ni[1] != orig.ni[1] {
+		t.Errorf("unexpected counts after a failed removal got:%d %v, want:%d %v", ld.n, ld.ni, orig.n, orig.ni)
+	}
+	if !mat.Equal(ld.mu, orig.mu) || !mat.Equal(ld.cw, orig.cw) || !mat.Equal(ld.cb, orig.cb) {
+		t.Error("statistics changed after a failed removal")
+	}
+	if !mat.Equal(ld.evecs, orig.evecs) {
+		t.Error("eigenvectors changed after a failed removal")
+	}
+	for _, row := range []int{0, 3} {
+		x := data.RawRowView(row)
+		got, err := ld.Predict(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := orig.Predict(x)
+		if got != want {
+			t.Errorf("unexpected prediction for row %d got:%d, want:%d", row, got, want)
+		}
+	}
+}
+
+func TestRemoveSampleClassSize(t *testing.T) {
+	data := mat.NewDense(7, 2, []float64{
+		1, 2,
+		2, 1,
+		3, 3,
+		6, 5,
+		7, 7,
+		5, 6,
+		8, 6,
+	})
+	labels := []int{0, 0, 0, 1, 1, 1, 1}
+	var ld LD
+	if err := ld.LinearDiscriminant(data, labels); err != nil {
+		t.Fatal(err)
+	}
+	if err := ld.RemoveSample(data.RawRowView(0), labels[0]); err != nil {
+		t.Fatal(err)
+	}
+	var orig LD
+	if err := orig.LinearDiscriminant(data.Slice(1, 7, 0, 2), labels[1:]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Class 0 has two samples left, and a fit needs at least two per class
+	if err := ld.RemoveSample(data.RawRowView(1), labels[1]); err == nil {
+		t.Fatal("expected an error removing down to one sample")
+	}
+	if ld.n != 6 || ld.ni[0] != 2 || ld.ni[1] != 4 {
+		t.Errorf("unexpected counts after a rejected removal got:%d %v", ld.n, ld.ni)
+	}
+	const epsilon = 1e-9
+	if !mat.EqualApprox(ld.mu, orig.mu, epsilon) || !mat.EqualApprox(ld.cw, orig.cw, epsilon) {
+		t.Error("statistics changed after a rejected removal")
+	}
+	if !ld.ok {
+		t.Error("model no longer fitted after a rejected removal")
+	}
+}
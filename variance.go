@@ -0,0 +1,53 @@
+package lda
+
+// ExplainedVarianceRatio returns the proportion of the between-class
+// variance explained by each discriminant, in the order of the components
+// returned by Transform. The ratios sum to 1. It returns nil if the model
+// has not been fit.
+func (ld *LD) ExplainedVarianceRatio() []float64 {
+	if !ld.ok {
+		return nil
+	}
+	values := ld.EigenValues()[:len(ld.dirs)]
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	ratios := make([]float64, len(values))
+	for i, v := range values {
+		ratios[i] = v / total
+	}
+	return ratios
+}
+
+// CumulativeExplainedVariance returns the running sum of
+// ExplainedVarianceRatio: entry i is the proportion of the between-class
+// variance explained by the first i+1 discriminants. It returns nil if the
+// model has not been fit.
+func (ld *LD) CumulativeExplainedVariance() []float64 {
+	ratios := ld.ExplainedVarianceRatio()
+	var sum float64
+	for i, r := range ratios {
+		sum += r
+		ratios[i] = sum
+	}
+	return ratios
+}
+
+// NumComponentsForVariance returns the smallest number of discriminants
+// that together explain at least the proportion threshold of the
+// between-class variance. It returns 0 if threshold is not in (0,1] or the
+// model has not been fit.
+func (ld *LD) NumComponentsForVariance(threshold float64) int {
+	if threshold <= 0 || threshold > 1 {
+		return 0
+	}
+	cumulative := ld.CumulativeExplainedVariance()
+	for i, c := range cumulative {
+		if c >= threshold {
+			return i + 1
+		}
+	}
+	// Rounding can leave the total just short of 1
+	return len(cumulative)
+}
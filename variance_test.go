@@ -0,0 +1,40 @@
+package lda
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCumulativeExplainedVariance(t *testing.T) {
+	var ld LD
+	if ld.CumulativeExplainedVariance() != nil {
+		t.Error("expected nil for an unfitted model")
+	}
+	if got := ld.NumComponentsForVariance(0.95); got != 0 {
+		t.Errorf("unexpected number of components for an unfitted model got:%d, want:0", got)
+	}
+	dataMatrix, labels, _ := loadIris(t)
+	if err := ld.LinearDiscriminant(dataMatrix, labels); err != nil {
+		t.Fatal(err)
+	}
+
+	ratios := ld.ExplainedVarianceRatio()
+	if len(ratios) != 2 {
+		t.Fatalf("unexpected number of ratios got:%d, want:2", len(ratios))
+	}
+	cumulative := ld.CumulativeExplainedVariance()
+	if math.Abs(cumulative[0]-ratios[0]) > 1e-15 || math.Abs(cumulative[1]-1) > 1e-12 {
+		t.Errorf("unexpected cumulative explained variance got:%v", cumulative)
+	}
+
+	for _, threshold := range []float64{0, 1.5} {
+		if got := ld.NumComponentsForVariance(threshold); got != 0 {
+			t.Errorf("unexpected number of components for threshold %v got:%d, want:0", threshold, got)
+		}
+	}
+	for threshold, want := range map[float64]int{0.95: 1, 0.999: 2, 1: 2} {
+		if got := ld.NumComponentsForVariance(threshold); got != want {
+			t.Errorf("unexpected number of components for threshold %v got:%d, want:%d", threshold, got, want)
+		}
+	}
+}